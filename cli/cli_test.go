@@ -403,6 +403,39 @@ func TestVersionCmd_Run_ReturnsWriteError(t *testing.T) {
 	assertExitCode(t, err, exit.IO)
 }
 
+func TestEval_Theme_ParsesBuiltins(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    theme
+		wantErr bool
+	}{
+		{name: "default", args: []string{"eval"}, want: themeAuto},
+		{name: "solarized", args: []string{"eval", "--theme=solarized"}, want: themeSolarized},
+		{name: "no-color", args: []string{"eval", "--theme=no-color"}, want: themeNoColor},
+		{name: "unknown", args: []string{"eval", "--theme=neon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var syntax syntax
+			parser := newTestParser(t, &syntax, io.Discard)
+			_, err := parser.Parse(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Parse() error = nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := syntax.Eval.Theme; got != tt.want {
+				t.Fatalf("Theme = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEval_Run_ReturnsLogOutputCreateError(t *testing.T) {
 	var syntax syntax
 	parser := newTestParser(t, &syntax, io.Discard)
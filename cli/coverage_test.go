@@ -323,11 +323,17 @@ func TestMsg_Constructors(t *testing.T) {
 	if got, ok := setEditMode(editLine)().(setEditModeMsg); !ok || got.mode != editLine || len(got.next) != 0 {
 		t.Fatalf("setEditMode(mode)() = %#v, want mode=%v next=[]", got, editLine)
 	}
-	if got := collect("in")(); got != (collectMsg{"in"}) {
-		t.Fatalf("collect(input)() = %#v, want %#v", got, collectMsg{"in"})
+	if got := collect("in")(); got != (collectMsg{input: "in"}) {
+		t.Fatalf("collect(input)() = %#v, want %#v", got, collectMsg{input: "in"})
 	}
-	if got := capture("in")(); got != (captureMsg{"in"}) {
-		t.Fatalf("capture(input)() = %#v, want %#v", got, captureMsg{"in"})
+	if got := collectScript("in")(); got != (collectMsg{input: "in", script: true}) {
+		t.Fatalf("collectScript(input)() = %#v, want script collectMsg", got)
+	}
+	if got := capture("in")(); got != (captureMsg{input: "in"}) {
+		t.Fatalf("capture(input)() = %#v, want %#v", got, captureMsg{input: "in"})
+	}
+	if got := captureDetached("in")(); got != (captureMsg{input: "in", detached: true}) {
+		t.Fatalf("captureDetached(input)() = %#v, want detached captureMsg", got)
 	}
 	if got := commit("t", "v")(); got != (commitMsg{"t", "v"}) {
 		t.Fatalf("commit(text, view)() = %#v, want %#v", got, commitMsg{"t", "v"})
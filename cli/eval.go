@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"io"
 	"slices"
 
 	"github.com/ardnew/aenv/exit"
@@ -14,6 +16,19 @@ type Eval struct {
 	logFlags
 	inputFlags
 
+	// Theme selects the color palette of interactive output.
+	Theme theme `help:"Color theme of interactive output (${enum})." enum:"auto,dark,light,solarized,no-color" default:"auto"`
+	// Accessible renders plain, labeled output suitable for screen readers.
+	Accessible bool `help:"Render plain, labeled output suitable for screen readers." env:"ACCESSIBLE"`
+	// RC names a file of inputs, one per line, evaluated on startup.
+	RC string `name:"rc" help:"Evaluate inputs from file, one per line, on startup." placeholder:"file" type:"existingfile"`
+	// Exec holds semicolon-separated inputs evaluated on startup, after RC.
+	Exec string `help:"Evaluate semicolon-separated inputs on startup, after --rc." placeholder:"script"`
+	// Record names a new file to which a transcript of the session is written.
+	Record string `help:"Record a session transcript to a new Markdown (.md) or asciicast (.cast) file, masking values of secret-like keys." placeholder:"file"`
+	// ResultsOut names a file to which each evaluation is appended as JSON.
+	ResultsOut string `name:"results-out" help:"Append each evaluation's input, result and metadata to file as JSON lines." placeholder:"file"`
+
 	ast lang.AST
 }
 
@@ -27,16 +42,76 @@ func (e Eval) Run(ctx context.Context) error {
 		"sources", len(e.Source),
 		"handlers", len(e.Log),
 		"verbose", e.Verbose,
+		"theme", e.Theme,
+		"accessible", e.Accessible,
 	), "command")
-	return withLogHandlers(e.logFlags, func() error {
+	return withLogHandlers(e.logFlags, func() (err error) {
 		if err := withSources(e.Source, &e); err != nil {
 			return err
 		}
+		opts, closeOpts, err := e.replOptions()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, withExitCode(closeOpts(), exit.IO))
+		}()
 		log.Debug(log.Attrs("cmd", "eval"))
-		return withExitCode(repLoop(ctx, e.ast), exit.OS)
+		return withExitCode(repLoop(ctx, e.ast, opts...), exit.OS)
 	})
 }
 
+// replOptions returns the REPL options selected by e's flags, and a function
+// that closes any files they opened.
+func (e Eval) replOptions() ([]option[repl], func() error, error) {
+	var closers []io.Closer
+	closeOpts := func() error {
+		var errs []error
+		for _, c := range closers {
+			errs = append(errs, c.Close())
+		}
+		return errors.Join(errs...)
+	}
+
+	script, err := e.script()
+	if err != nil {
+		return nil, closeOpts, err
+	}
+
+	var rec *recorder
+	if e.Record != "" {
+		var closer io.Closer
+		rec, closer, err = openRecorder(e.Record)
+		if err != nil {
+			return nil, closeOpts, wrapPathError(err)
+		}
+		closers = append(closers, closer)
+	}
+
+	var results *resultSink
+	if e.ResultsOut != "" {
+		var closer io.Closer
+		results, closer, err = openResults(e.ResultsOut)
+		if err != nil {
+			return nil, closeOpts, errors.Join(wrapPathError(err), closeOpts())
+		}
+		closers = append(closers, closer)
+	}
+
+	log.Debug(log.Attrs(
+		"script", len(script),
+		"record", e.Record,
+		"results-out", e.ResultsOut,
+	), "repl options")
+	return []option[repl]{
+		withTheme(e.Theme),
+		withAccessible(e.Accessible),
+		withScript(script...),
+		withRecorder(rec),
+		withResults(results),
+	}, closeOpts, nil
+}
+
 func (e *Eval) Write(b []byte) (int, error) {
 	nb, err := e.ast.Write(b)
 	if err != nil {
@@ -45,3 +120,16 @@ func (e *Eval) Write(b []byte) (int, error) {
 	}
 	return nb, nil
 }
+
+// script returns the startup inputs from --rc followed by those from --exec.
+func (e Eval) script() ([]string, error) {
+	var script []string
+	if e.RC != "" {
+		entries, err := readScriptFile(e.RC)
+		if err != nil {
+			return nil, wrapPathError(err)
+		}
+		script = entries
+	}
+	return append(script, splitScript(e.Exec)...), nil
+}
@@ -2,14 +2,23 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ardnew/aenv/log"
 )
 
+// historyFlushInterval bounds how long a recorded entry is buffered in memory
+// before it is appended to the history file.
+const historyFlushInterval = time.Second
+
 // history records evaluated inputs and recalls them by navigation.
 //
 // index points one past the newest entry while the user edits a draft; prev and
@@ -19,6 +28,8 @@ type history struct {
 	entries []string
 	index   int
 	draft   string
+
+	w *historyWriter // shared by copies of the REPL model
 }
 
 // loadHistory reads entries from path. A missing or unreadable file yields an
@@ -28,12 +39,16 @@ func loadHistory(path string) history {
 	if path == "" {
 		return h
 	}
+	h.w = &historyWriter{path: path}
 	file, err := os.Open(path)
 	if err != nil {
 		return h
 	}
 	defer func() { _ = file.Close() }()
 
+	// Lines that fail to unquote, such as one truncated by a crash mid-write,
+	// are skipped rather than discarding the entire history.
+	skipped := 0
 	sin := bufio.NewScanner(file)
 	sin.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for sin.Scan() {
@@ -43,11 +58,18 @@ func loadHistory(path string) history {
 		}
 		entry, err := strconv.Unquote(line)
 		if err != nil {
+			skipped++
 			continue
 		}
 		h.entries = append(h.entries, entry)
 	}
-	_ = sin.Err()
+	if err := sin.Err(); err != nil || skipped > 0 {
+		log.Debug(log.Attrs(
+			"path", path,
+			"skipped", skipped,
+			"error", err,
+		), "history recovered")
+	}
 	h.index = len(h.entries)
 	return h
 }
@@ -116,18 +138,121 @@ func (h *history) next() (string, bool) {
 }
 
 func (h *history) persist(entry string) {
-	if h.path == "" {
+	if h.w == nil {
 		log.Trace(log.Attrs("reason", "memory-only"), "history persist skip")
 		return
 	}
 	log.Trace(log.Attrs("path", h.path, "len", len(entry)), "history persist")
-	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+	h.w.append(entry)
+}
+
+// close writes any buffered entries and syncs the history file.
+func (h *history) close() {
+	if h.w != nil {
+		h.w.close()
+	}
+}
+
+// historyWriter appends entries to a history file in batches.
+//
+// Entries are buffered and written at most historyFlushInterval after being
+// recorded; close writes the remainder and syncs the file. The first write
+// error is logged and disables the writer, so a read-only or full disk costs
+// one warning rather than one per entry. It is safe for concurrent use.
+type historyWriter struct {
+	mu     sync.Mutex
+	path   string
+	buf    bytes.Buffer
+	timer  *time.Timer
+	dirty  bool // written since the last sync
+	failed bool
+	closed bool
+}
+
+func (w *historyWriter) append(entry string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failed || w.closed {
 		return
 	}
-	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
+	w.buf.WriteString(strconv.Quote(entry))
+	w.buf.WriteByte('\n')
+	if w.timer == nil {
+		w.timer = time.AfterFunc(historyFlushInterval, w.flush)
+	}
+}
+
+func (w *historyWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLocked(false)
+}
+
+func (w *historyWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
 		return
 	}
-	defer func() { _ = file.Close() }()
-	_, _ = file.WriteString(strconv.Quote(entry) + "\n")
+	w.flushLocked(true)
+	w.closed = true
+}
+
+func (w *historyWriter) flushLocked(durable bool) {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if w.failed || (w.buf.Len() == 0 && (!durable || !w.dirty)) {
+		return
+	}
+	if err := w.write(durable); err != nil {
+		w.failed = true
+		w.buf.Reset()
+		log.Warn(log.Attrs("path", w.path, "error", err), "history persist stopped")
+	}
+}
+
+func (w *historyWriter) write(durable bool) (err error) {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, file.Close()) }()
+
+	if err := terminateLine(file); err != nil {
+		return err
+	}
+	if _, err := w.buf.WriteTo(file); err != nil {
+		return err
+	}
+	w.dirty = true
+	if durable {
+		if err := file.Sync(); err != nil {
+			return err
+		}
+		w.dirty = false
+	}
+	return nil
+}
+
+// terminateLine appends a newline to file if its last byte is not one, so that
+// new entries are not concatenated onto a line truncated by an earlier crash.
+func terminateLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	_, err = file.Write([]byte{'\n'})
+	return err
 }
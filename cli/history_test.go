@@ -1,8 +1,13 @@
 package cli
 
 import (
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/ardnew/aenv/log"
 )
 
 func TestHistory_RecordSkipsBlankAndDuplicates(t *testing.T) {
@@ -55,6 +60,7 @@ func TestHistory_PersistRoundTrip(t *testing.T) {
 	h := loadHistory(path)
 	h.record("plain")
 	h.record("multi\nline")
+	h.close()
 
 	reloaded := loadHistory(path)
 	want := []string{"plain", "multi\nline"}
@@ -70,3 +76,69 @@ func TestHistory_PersistRoundTrip(t *testing.T) {
 		t.Fatalf("reloaded index = %d, want %d", reloaded.index, len(want))
 	}
 }
+
+func TestHistory_Record_BuffersUntilClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h := loadHistory(path)
+	h.record("one")
+	h.record("two")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("history file exists before flush (err = %v), want buffered", err)
+	}
+
+	h.close()
+	h.record("three") // dropped: the writer is closed
+	if got, want := loadHistory(path).entries, []string{"one", "two"}; !slices.Equal(got, want) {
+		t.Fatalf("reloaded entries = %q, want %q", got, want)
+	}
+}
+
+func TestHistory_Load_RecoversTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(path, []byte("\"one\"\n\"tw"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := loadHistory(path)
+	if got, want := h.entries, []string{"one"}; !slices.Equal(got, want) {
+		t.Fatalf("entries = %q, want %q", got, want)
+	}
+	h.record("three")
+	h.close()
+
+	if got, want := loadHistory(path).entries, []string{"one", "three"}; !slices.Equal(got, want) {
+		t.Fatalf("reloaded entries = %q, want %q", got, want)
+	}
+}
+
+func TestHistory_Close_WriteErrorDisablesWriter(t *testing.T) {
+	restoreDefaultLogger(t)
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf terminalWriter
+	driver, err := log.New(log.HandlerOptions{Writer: &buf, Format: log.FormatText, Level: log.LevelWarn})
+	if err != nil {
+		t.Fatalf("log.New() error = %v", err)
+	}
+	log.SetDefault(driver)
+
+	h := loadHistory(filepath.Join(blocker, "history"))
+	h.record("one")
+	h.w.flush()
+	if !h.w.failed {
+		t.Fatal("failed = false after write error, want true")
+	}
+	h.record("two")
+	h.close()
+	if got := h.entries; !slices.Equal(got, []string{"one", "two"}) {
+		t.Fatalf("entries = %q, want in-memory history kept", got)
+	}
+	if got := strings.Count(buf.String(), "history persist stopped"); got != 1 {
+		t.Fatalf("logged %d write warnings, want 1:\n%s", got, buf.String())
+	}
+}
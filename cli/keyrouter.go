@@ -131,6 +131,15 @@ func (l repl) handleKeyPress(msg tea.KeyPressMsg) (repl, tea.Cmd) {
 		return l, collect(l.edit.value())
 
 	case key.Matches(msg, l.keys.quit):
+		if l.evaluating() {
+			// Interrupt the running evaluation, along with any queued input and
+			// startup script, rather than the whole session. Queued inputs are
+			// still echoed, each followed by its own cancellation notice.
+			log.Debug(msgAttr(msg, "action", "cancel", "pending", len(l.pending)))
+			l.cancelEval()
+			l.dropped = len(l.pending)
+			return l.stopScript(), nil
+		}
 		log.Debug(msgAttr(msg, "action", "quit"))
 		return l, tea.Quit
 
@@ -144,6 +153,11 @@ func (l repl) handleKeyPress(msg tea.KeyPressMsg) (repl, tea.Cmd) {
 		log.Debug(msgAttr(msg, "action", "preview"))
 
 	case key.Matches(msg, l.keys.screen):
+		if l.accessible {
+			log.Debug(msgAttr(msg, "action", "toggle", "reason", "accessible"), "alt-screen skip")
+			forwardText = false
+			break
+		}
 		log.Debug(msgAttr(msg, "action", "toggle", "alt-screen", !l.altScreen))
 		l.altScreen = !l.altScreen
 		l = l.syncViewportSize()
@@ -41,7 +41,7 @@ func (l repl) onReady() (repl, error) {
 // handleReady focuses the active editor, wires up the REPL as the terminal
 // log destination (onReady), and starts the drainLog goroutine exactly once
 // (guarded by logOnce -- readyMsg can fire more than once, e.g. on every
-// terminal resize).
+// terminal resize). It also starts the startup script, if any (see script.go).
 //
 // NOTE: this case fell through to Update's shared tail (syncViewportSize)
 // rather than returning early; that is reproduced explicitly here.
@@ -56,7 +56,10 @@ func (l repl) handleReady() (repl, tea.Cmd) {
 	l = r
 	l.log1.Do(func() { go l.drainLog() })
 
-	return l.syncViewportSize(), focus
+	var script tea.Cmd
+	l, script = l.startScript()
+
+	return l.syncViewportSize(), tea.Batch(focus, script)
 }
 
 // drainLog relays log lines queued by Write to the running [tea.Program] one
@@ -1,6 +1,12 @@
 package cli
 
-import tea "charm.land/bubbletea/v2"
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/ardnew/aenv/lang"
+)
 
 // logMsg is used to queue a message written to the REPL output stream.
 //
@@ -36,19 +42,44 @@ func setEditMode(mode editMode, next ...tea.Msg) tea.Cmd {
 }
 
 // Executing REPL input requires 4 render cycles to synchronize the persistent
-// models and output stream without visual artifacts.
+// models and output stream without visual artifacts. The evaluation itself
+// runs in the background, delivering its result in a 5th message.
 type (
-	collectMsg  struct{ input string }      // 1. save input; clear view
-	captureMsg  struct{ input string }      // 2. create input snapshot; reset
-	commitMsg   struct{ text, view string } // 3. draw input snapshot
-	evaluateMsg struct{ input string }      // 4. evaluate input; draw output
+	collectMsg struct { // 1. save input; clear view
+		input  string
+		script bool // startup script entry; skip history, leave the editor alone
+	}
+	captureMsg struct { // 2. create input snapshot; reset
+		input    string
+		detached bool // input is not in the live editor; skip the reset
+	}
+	commitMsg    struct{ text, view string } // 3. draw input snapshot
+	evaluateMsg  struct{ input string }      // 4. start evaluating input
+	evaluatedMsg struct {                    // 5. draw output
+		input   string
+		ast     lang.AST
+		output  string
+		err     error
+		elapsed time.Duration
+	}
 )
 
-func collect(input string) tea.Cmd     { return func() tea.Msg { return collectMsg{input} } }
-func capture(input string) tea.Cmd     { return func() tea.Msg { return captureMsg{input} } }
+func collect(input string) tea.Cmd     { return func() tea.Msg { return collectMsg{input: input} } }
+func capture(input string) tea.Cmd     { return func() tea.Msg { return captureMsg{input: input} } }
 func commit(text, view string) tea.Cmd { return func() tea.Msg { return commitMsg{text, view} } }
 func evaluate(input string) tea.Cmd    { return func() tea.Msg { return evaluateMsg{input} } }
 
+// collectScript is like collect for a startup script entry (see script.go).
+func collectScript(input string) tea.Cmd {
+	return func() tea.Msg { return collectMsg{input: input, script: true} }
+}
+
+// captureDetached is like capture for input that was already removed from the
+// live editor, such as input queued while another was being evaluated.
+func captureDetached(input string) tea.Cmd {
+	return func() tea.Msg { return captureMsg{input: input, detached: true} }
+}
+
 type (
 	readyMsg struct{}
 	resetMsg struct{}
@@ -5,6 +5,7 @@ import (
 	"slices"
 	"strings"
 
+	"charm.land/bubbles/v2/spinner"
 	tea "charm.land/bubbletea/v2"
 )
 
@@ -50,7 +51,8 @@ func (l repl) syncViewportSize() repl {
 		return l
 	}
 	atBottom := l.screen.AtBottom()
-	editLines := max(1, lineCount(l.edit.View().Content))
+	editContent, _ := l.editorView()
+	editLines := max(1, lineCount(editContent))
 	height := max(0, l.edit.bounds.Y-editLines)
 	l.screen.SetWidth(l.edit.bounds.X)
 	l.screen.SetHeight(height)
@@ -106,12 +108,53 @@ func (l repl) handleLog(msg logMsg) (repl, tea.Cmd) {
 	return l, tea.Println(strings.TrimRight(s, "\r\n"))
 }
 
+// handleSpinnerTick advances the evaluation spinner. Ticks arriving after the
+// evaluation finished are dropped, which stops the spinner's tick loop.
+func (l repl) handleSpinnerTick(msg spinner.TickMsg) (repl, tea.Cmd) {
+	if !l.evaluating() {
+		return l, nil
+	}
+	var cmd tea.Cmd
+	l.spin, cmd = l.spin.Update(msg)
+	return l, cmd
+}
+
+// statusLine renders the indicator drawn above the editor while an evaluation
+// is running, or "" when idle. Accessible mode omits the animated spinner so
+// that screen readers are not flooded with redraws.
+func (l repl) statusLine() string {
+	if !l.evaluating() {
+		return ""
+	}
+	status := fmt.Sprintf("evaluating (%s to cancel)", l.keys.quit.Help().Key)
+	if l.accessible {
+		return status
+	}
+	return l.spin.View() + " " + status
+}
+
+// editorView renders the active editor preceded by the status line, if any,
+// and returns the number of rows the status line occupies.
+func (l repl) editorView() (string, int) {
+	content := l.edit.View().Content
+	if status := l.statusLine(); status != "" {
+		return status + "\n" + content, 1
+	}
+	return content, 0
+}
+
 // transcriptView renders the plain (non-alt-screen) mode: only the active
 // editor is drawn; previously evaluated input/output are written directly to
 // the terminal's natural scrollback via tea.Println (see pipeline.go).
 func (l repl) transcriptView(cursor *tea.Cursor) tea.View {
 	var v tea.View
-	v.SetContent(l.edit.View().Content)
+	content, status := l.editorView()
+	v.SetContent(content)
+	if cursor != nil && status > 0 {
+		shifted := *cursor
+		shifted.Y += status
+		cursor = &shifted
+	}
 	v.Cursor = cursor
 	v.AltScreen = false
 	return v
@@ -121,7 +164,7 @@ func (l repl) transcriptView(cursor *tea.Cursor) tea.View {
 // with the active editor pinned to the bottom.
 func (l repl) altScreenView(cursor *tea.Cursor) tea.View {
 	var v tea.View
-	editContent := l.edit.View().Content
+	editContent, status := l.editorView()
 	l = l.syncViewportSize()
 	output := l.outputRegionView()
 	if output != "" {
@@ -131,7 +174,7 @@ func (l repl) altScreenView(cursor *tea.Cursor) tea.View {
 	}
 	if cursor != nil {
 		shifted := *cursor
-		shifted.Y += l.screen.Height()
+		shifted.Y += l.screen.Height() + status
 		cursor = &shifted
 	}
 	v.Cursor = cursor
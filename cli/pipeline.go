@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -30,18 +33,32 @@ func lineCaptureWidth(edit LineEdit, viewportWidth int) int {
 // reset of the live editor. This file holds the handler for each stage, kept
 // together (rather than split across concern-specific files) so the whole
 // cycle can be read start-to-finish in one place. See msg.go for the
-// corresponding collectMsg/captureMsg/commitMsg/evaluateMsg/resetMsg types.
+// corresponding collectMsg/captureMsg/commitMsg/evaluateMsg/evaluatedMsg/resetMsg
+// types.
 //
 //  1. handleCollect: normalize + record the submitted input to history, then
 //     blur the editor so the next cycle can capture its unfocused appearance.
+//     Startup script entries skip both and go straight to capture.
+//     Input submitted while another input is still running through the cycle
+//     is queued instead, and the editor is reset right away.
 //  2. handleCapture: render a fresh, unfocused snapshot of the input using a
 //     brand new editor model (not the live one -- see the comment inside for
 //     why), then request a reset before committing that snapshot to the
 //     output stream.
 //  3. handleCommit: write the captured snapshot to the output stream/buffer,
 //     then start evaluation.
-//  4. handleEvaluate: feed the input to the AST, write the result to the
-//     output stream/buffer, and quit if the user requested eval-and-quit.
+//  4. handleEvaluate: start feeding the input to the AST in the background
+//     and show a spinner.
+//  5. handleEvaluated: write the result to the output stream/buffer, then
+//     capture the next queued input, quit if the user requested
+//     eval-and-quit, or collect the next startup script entry (script.go).
+//
+// Queued input is only captured once its turn comes, so that every input is
+// echoed directly above its own result.
+//
+// The quit key cancels a running evaluation, along with the queue and any
+// remaining startup script entries, instead of quitting (see keyrouter.go).
+// Each queued input is still echoed, followed by a cancellation notice.
 //
 // handleReset (triggered between capture and commit) clears and refocuses the
 // live editor so it starts empty rather than carrying over the captured
@@ -55,9 +72,25 @@ func (l repl) handleCollect(msg collectMsg) (repl, tea.Cmd) {
 		"len", len(text),
 		"lines", lineCount(text),
 	))
+	if msg.script {
+		// Script entries are not typed by the user, so they stay out of history
+		// and never touch whatever the user is typing in the live editor.
+		if l.running {
+			l.pending = append(l.pending, text)
+			return l, nil
+		}
+		l.running = true
+		return l, captureDetached(text)
+	}
 	// Write unstyled text to history so that it can be recalled directly
 	// without formatting artifacts.
 	l.hist.record(text)
+	if l.running {
+		// Hold the input back until the running one has its result.
+		l.pending = append(l.pending, text)
+		return l, reset
+	}
+	l.running = true
 	// Remove focus and capture the view in the next render cycle.
 	var focus tea.Cmd
 	l.edit, focus = l.edit.setValue("").setFocus(editNone)
@@ -73,19 +106,28 @@ func (l repl) handleCapture(msg captureMsg) (repl, tea.Cmd) {
 	// relative to a new, cleared edit model. Otherwise, the captured views
 	// begin collecting vertical gaps when the edit model reaches the bottom of
 	// the terminal window.
+	cmds := []tea.Cmd{reset}
+	if msg.detached {
+		// The live editor was left alone, so there is nothing to reset.
+		cmds = nil
+	}
 	var view tea.View
+	if l.accessible {
+		view.SetContent(labelLines(inputLabel, msg.input))
+		return l, tea.Sequence(append(cmds, commit(msg.input, view.Content))...)
+	}
 	switch l.edit.mode {
 	case editLine:
-		edit := makeLineEdit(msg.input).setStyle(l.edit.style.isDark)
+		edit := makeLineEdit(msg.input).setStyle(l.edit.style.theme, l.edit.style.isDark)
 		edit.SetWidth(lineCaptureWidth(edit, l.edit.line.Width()))
 		view = edit.View()
 
 	case editArea:
 		edit := makeAreaEdit(msg.input)
 		edit.SetWidth(l.edit.area.Width())
-		view = edit.setStyle(l.edit.style.isDark).View()
+		view = edit.setStyle(l.edit.style.theme, l.edit.style.isDark).View()
 	}
-	return l, tea.Sequence(reset, commit(msg.input, view.Content))
+	return l, tea.Sequence(append(cmds, commit(msg.input, view.Content))...)
 }
 
 func (l repl) handleCommit(msg commitMsg) (repl, tea.Cmd) {
@@ -101,24 +143,104 @@ func (l repl) handleCommit(msg commitMsg) (repl, tea.Cmd) {
 }
 
 func (l repl) handleEvaluate(msg evaluateMsg) (repl, tea.Cmd) {
-	log.Debug(msgAttr(msg, "mode", l.edit.mode))
-	// evaluate is defined with a value receiver for immutability.
-	r, output, err := l.evaluate(msg.input)
-	if err != nil {
-		// Return the original [repl] to avoid preserving an invalid or incomplete
-		// AST in its model, which could otherwise reproduce related errors.
-		return l, fault(err)
+	log.Debug(msgAttr(msg,
+		"mode", l.edit.mode,
+		"pending", len(l.pending),
+		"dropped", l.dropped,
+	))
+	if l.dropped > 0 {
+		// Queued behind an evaluation that was canceled.
+		l.dropped--
+		return l, func() tea.Msg {
+			return evaluatedMsg{input: msg.input, err: context.Canceled}
+		}
+	}
+	ctx, cancel := context.WithCancel(l.ctx)
+	l.cancelEval = cancel
+	return l.syncViewportSize(), tea.Batch(
+		l.evaluateAsync(ctx, cancel, msg.input),
+		l.spin.Tick,
+	)
+}
+
+func (l repl) handleEvaluated(msg evaluatedMsg) (repl, tea.Cmd) {
+	log.Debug(msgAttr(msg,
+		"len", len(msg.output),
+		"pending", len(l.pending),
+		"error", msg.err,
+	))
+	l.cancelEval = nil
+	l = l.syncViewportSize()
+	output := msg.output
+	canceled := errors.Is(msg.err, context.Canceled)
+	l.results.write(msg, canceled)
+	switch {
+	case canceled:
+		output = "evaluation canceled"
+	case msg.err != nil:
+		// Discard the evaluated AST to avoid preserving an invalid or incomplete
+		// AST in the model, which could otherwise reproduce related errors.
+		return l, fault(msg.err)
+	default:
+		l.ast = msg.ast
+	}
+	l.rec.record(msg.input, output, msg.elapsed)
+	if l.accessible {
+		output = labelLines(resultLabel, output)
 	}
 	var batch []tea.Cmd
 	if l.altScreen {
-		r = r.appendOutput(output)
+		l = l.appendOutput(output)
 	} else {
 		batch = append(batch, tea.Println(output))
 	}
-	if l.quitting {
-		batch = append(batch, quit)
+	var next tea.Cmd
+	switch {
+	case len(l.pending) > 0:
+		next = captureDetached(l.pending[0])
+		l.pending = l.pending[1:]
+	case l.quitting:
+		l.running = false
+		next = quit
+	default:
+		l.running = false
+		l, next = l.nextScript()
+	}
+	return l, tea.Sequence(append(batch, next)...)
+}
+
+// evaluating reports whether an evaluation is running in the background.
+func (l repl) evaluating() bool { return l.cancelEval != nil }
+
+// evaluateAsync returns a command that evaluates input off the update loop
+// and reports the result as an [evaluatedMsg].
+//
+// If ctx is canceled first, the command returns immediately with ctx's error;
+// the abandoned evaluation still runs to completion, but its result is
+// discarded. The evaluation only ever sees its own copy of the model, so
+// abandoning it cannot corrupt the live AST.
+func (l repl) evaluateAsync(
+	ctx context.Context, cancel context.CancelFunc, input string,
+) tea.Cmd {
+	return func() tea.Msg {
+		defer cancel()
+		if err := ctx.Err(); err != nil {
+			return evaluatedMsg{input: input, err: err}
+		}
+		start := time.Now()
+		done := make(chan evaluatedMsg, 1)
+		go func() {
+			r, output, err := l.evaluate(input)
+			done <- evaluatedMsg{input: input, ast: r.ast, output: output, err: err}
+		}()
+		select {
+		case msg := <-done:
+			msg.elapsed = time.Since(start)
+			return msg
+		case <-ctx.Done():
+			return evaluatedMsg{input: input, err: ctx.Err(), elapsed: time.Since(start)}
+		}
 	}
-	return r, tea.Sequence(batch...)
 }
 
 func (l repl) handleReset(msg resetMsg) (repl, tea.Cmd) {
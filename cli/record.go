@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ardnew/aenv/log"
+)
+
+// recordFormat selects the file format of a session transcript.
+type recordFormat int
+
+const (
+	recordMarkdown recordFormat = iota // fenced input/output blocks
+	recordCast                         // asciicast v2 terminal recording
+)
+
+// Dimensions declared in asciicast headers. The REPL may record before (or
+// without) learning the terminal size, so a fixed size is declared instead.
+const (
+	castWidth  = 80
+	castHeight = 24
+)
+
+// recorder appends each evaluated input and its output to a session
+// transcript. It is shared by pointer among copies of the [repl] model.
+//
+// Write errors are logged once, after which recording stops, rather than
+// interrupting the session or repeating the same error for every input.
+type recorder struct {
+	w      io.Writer
+	format recordFormat
+	start  time.Time
+	failed bool
+}
+
+// recordFormatOf returns the transcript format implied by the extension of
+// path: asciicast for ".cast", otherwise Markdown.
+func recordFormatOf(path string) recordFormat {
+	if strings.EqualFold(filepath.Ext(path), ".cast") {
+		return recordCast
+	}
+	return recordMarkdown
+}
+
+// openRecorder creates the transcript file at path. It refuses to overwrite an
+// existing file, which may be the transcript of an earlier session.
+func openRecorder(path string) (*recorder, io.Closer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	rec, err := makeRecorder(file, recordFormatOf(path), time.Now())
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	log.Debug(log.Attrs("path", path, "format", rec.format), "record session")
+	return rec, file, nil
+}
+
+// makeRecorder returns a recorder writing format to w, writing any header the
+// format requires.
+func makeRecorder(w io.Writer, format recordFormat, start time.Time) (*recorder, error) {
+	rec := &recorder{w: w, format: format, start: start}
+	if format == recordCast {
+		header, err := json.Marshal(map[string]any{
+			"version":   2,
+			"width":     castWidth,
+			"height":    castHeight,
+			"timestamp": start.Unix(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", header); err != nil {
+			return nil, err
+		}
+	}
+	return rec, nil
+}
+
+// record appends an evaluated input and its output to the transcript, where
+// elapsed is the time taken to evaluate the input. Sensitive values in both
+// are masked (see [redact]).
+func (r *recorder) record(input, output string, elapsed time.Duration) {
+	if r == nil || r.failed {
+		return
+	}
+	input, output = redact(input), redact(output)
+	var err error
+	switch r.format {
+	case recordMarkdown:
+		err = r.recordMarkdown(input, output)
+	case recordCast:
+		err = r.recordCast(input, output, elapsed)
+	}
+	if err != nil {
+		r.failed = true
+		log.Warn(log.Attrs("error", err), "record session stopped")
+	}
+}
+
+func (r *recorder) recordMarkdown(input, output string) error {
+	_, err := fmt.Fprintf(r.w, "%s\n\n%s\n\n",
+		fenced("aenv", input), fenced("", output))
+	return err
+}
+
+// recordCast writes the input as of its submission, elapsed before now, and
+// the output as of now, so that replay shows how long evaluation took.
+func (r *recorder) recordCast(input, output string, elapsed time.Duration) error {
+	done := time.Since(r.start)
+	submitted := max(0, done-elapsed)
+	for _, event := range []struct {
+		at   time.Duration
+		data string
+	}{
+		{submitted, "❯ " + strings.ReplaceAll(input, "\n", "\r\n  ") + "\r\n"},
+		{done, strings.ReplaceAll(strings.TrimRight(output, "\r\n"), "\n", "\r\n") + "\r\n"},
+	} {
+		event, err := json.Marshal([]any{event.at.Seconds(), "o", event.data})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(r.w, "%s\n", event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sensitivePattern matches a key that names a secret (e.g., "password",
+// "api_key" or "authToken"), its ":" or "=" separator, and its value. Values
+// are matched as a quoted string, a quoted string escaped within a JSON string
+// (as in the source of an evaluated AST), or a bare word.
+var sensitivePattern = regexp.MustCompile(`(?i)(` +
+	`[\w.-]*(?:secret|token|passw(?:or)?d|pwd|credential|(?:\b|[_.-])(?:api)?keys?\b)[\w.-]*` +
+	`(?:\\?")?\s*[:=]\s*)` +
+	`("(?:[^"\\]|\\.)*"|\\"(?:[^\\]|\\[^"])*\\"|[^\s,;{}\[\]"\\]+)`)
+
+// redactedValue replaces the value of each key matched by sensitivePattern.
+const redactedValue = "***"
+
+// redact returns text with the value of each key that names a secret masked,
+// keeping any quotes around the value so that the result stays well-formed.
+func redact(text string) string {
+	return sensitivePattern.ReplaceAllStringFunc(text, func(match string) string {
+		sub := sensitivePattern.FindStringSubmatch(match)
+		key, value := sub[1], sub[2]
+		switch {
+		case strings.HasPrefix(value, `\"`):
+			return key + `\"` + redactedValue + `\"`
+		case strings.HasPrefix(value, `"`):
+			return key + `"` + redactedValue + `"`
+		}
+		return key + redactedValue
+	})
+}
+
+// fenced returns text as a Markdown code block with the given info string.
+// The fence is one backtick longer than the longest backtick run in text, so
+// text can never close its own block.
+func fenced(info, text string) string {
+	longest, run := 0, 0
+	for _, c := range text {
+		if c == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + info + "\n" + strings.TrimRight(text, "\r\n") + "\n" + fence
+}
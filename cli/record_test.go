@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFenced_OutrunsBacktickRuns(t *testing.T) {
+	tests := []struct {
+		name string
+		info string
+		text string
+		want string
+	}{
+		{name: "plain", info: "aenv", text: "a : 1\n", want: "```aenv\na : 1\n```"},
+		{name: "short run", text: "x `y` z", want: "```\nx `y` z\n```"},
+		{name: "fence run", text: "```\ncode\n```", want: "````\n```\ncode\n```\n````"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fenced(tt.info, tt.text); got != tt.want {
+				t.Fatalf("fenced(%q, %q) = %q, want %q", tt.info, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordFormatOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want recordFormat
+	}{
+		{path: "session.md", want: recordMarkdown},
+		{path: "session", want: recordMarkdown},
+		{path: "session.cast", want: recordCast},
+		{path: "SESSION.CAST", want: recordCast},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := recordFormatOf(tt.path); got != tt.want {
+				t.Fatalf("recordFormatOf(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecorder_Record_Markdown(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := makeRecorder(&buf, recordMarkdown, time.Now())
+	if err != nil {
+		t.Fatalf("makeRecorder() error = %v", err)
+	}
+	rec.record("foo", "bar\n", 0)
+	want := "```aenv\nfoo\n```\n\n```\nbar\n```\n\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestRecorder_Record_Cast(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := makeRecorder(&buf, recordCast, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("makeRecorder() error = %v", err)
+	}
+	rec.record("a\nb", "out", 2*time.Second)
+
+	sc := bufio.NewScanner(&buf)
+	if !sc.Scan() {
+		t.Fatal("transcript missing header")
+	}
+	var header map[string]any
+	if err := json.Unmarshal(sc.Bytes(), &header); err != nil {
+		t.Fatalf("header %q: %v", sc.Text(), err)
+	}
+	if header["version"] != float64(2) {
+		t.Fatalf("header version = %v, want 2", header["version"])
+	}
+
+	var data []string
+	var at []float64
+	for sc.Scan() {
+		var event []any
+		if err := json.Unmarshal(sc.Bytes(), &event); err != nil {
+			t.Fatalf("event %q: %v", sc.Text(), err)
+		}
+		if len(event) != 3 || event[1] != "o" {
+			t.Fatalf("event = %v, want [time, \"o\", data]", event)
+		}
+		at = append(at, event[0].(float64))
+		data = append(data, event[2].(string))
+	}
+	if want := []string{"❯ a\r\n  b\r\n", "out\r\n"}; strings.Join(data, "|") != strings.Join(want, "|") {
+		t.Fatalf("event data = %q, want %q", data, want)
+	}
+	if delay := at[1] - at[0]; delay < 1.999 || delay > 2.001 {
+		t.Fatalf("event times = %v, want output 2s after input", at)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "bare value", text: "password : hunter2", want: "password : ***"},
+		{name: "quoted value", text: `api_key : "a b"`, want: `api_key : "***"`},
+		{name: "camel case key", text: "authToken=abc;", want: "authToken=***;"},
+		{name: "nested", text: "db { secret : x\n user : bob }", want: "db { secret : ***\n user : bob }"},
+		{name: "json field", text: `{"token":"abc","n":1}`, want: `{"token":"***","n":1}`},
+		{
+			name: "json escaped source",
+			text: `{"src":"key : \"abc\"\npwd : abc\n"}`,
+			want: `{"src":"key : \"***\"\npwd : ***\n"}`,
+		},
+		{name: "other keys", text: "monkey : banana\nuser : bob", want: "monkey : banana\nuser : bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redact(tt.text); got != tt.want {
+				t.Fatalf("redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecorder_Record_RedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := makeRecorder(&buf, recordMarkdown, time.Now())
+	if err != nil {
+		t.Fatalf("makeRecorder() error = %v", err)
+	}
+	rec.record("token : abc", `{"src":"token : abc\n"}`, 0)
+	if got := buf.String(); strings.Contains(got, "abc") {
+		t.Fatalf("transcript = %q, want secret masked", got)
+	}
+}
+
+func TestOpenRecorder_RefusesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.md")
+	if err := os.WriteFile(path, []byte("earlier"), 0o644); err != nil {
+		t.Fatalf("write transcript: %v", err)
+	}
+	if _, _, err := openRecorder(path); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("openRecorder() error = %v, want %v", err, fs.ErrExist)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "earlier" {
+		t.Fatalf("transcript = %q after refused open, want unchanged", b)
+	}
+}
+
+func TestRecorder_Record_StopsAfterWriteError(t *testing.T) {
+	restoreDefaultLogger(t)
+	rec, err := makeRecorder(errorWriter{}, recordMarkdown, time.Now())
+	if err != nil {
+		t.Fatalf("makeRecorder() error = %v", err)
+	}
+	rec.record("a", "b", 0)
+	if !rec.failed {
+		t.Fatal("failed = false after write error, want true")
+	}
+	rec.record("c", "d", 0) // must not panic or retry
+
+	var nilRec *recorder
+	nilRec.record("e", "f", 0) // recording disabled
+}
+
+func TestRepl_Record_WritesEvaluatedInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.md")
+	rec, closer, err := openRecorder(path)
+	if err != nil {
+		t.Fatalf("openRecorder() error = %v", err)
+	}
+	m := newREPL(t, withHistory(""), withRecorder(rec))
+	_, _ = runEvaluate(t, m, "zqxv")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read transcript: %v", err)
+	}
+	if got := string(b); !strings.HasPrefix(got, "```aenv\nzqxv\n```\n") {
+		t.Fatalf("transcript = %q, want fenced input first", got)
+	}
+}
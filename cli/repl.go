@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/spinner"
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 
@@ -30,9 +31,13 @@ const logQueueSize = 256
 // Its behavior is implemented across several files, grouped by concern:
 //   - repl.go: model definition, lifecycle (Init/Update dispatch), construction.
 //   - keyrouter.go: key bindings and key-press routing/actions.
-//   - pipeline.go: the collect/capture/commit/evaluate/reset eval cycle.
+//   - pipeline.go: the collect/capture/commit/evaluate/reset eval cycle and
+//     background evaluation.
 //   - output.go: the output buffer/viewport and View rendering.
 //   - logsink.go: wiring the REPL as the destination for terminal log output.
+//   - script.go: startup scripts evaluated before interactive input.
+//   - record.go: session transcripts of evaluated input and output.
+//   - results.go: machine-readable JSON lines of each evaluation.
 type repl struct {
 	app *tea.Program
 	ctx context.Context
@@ -50,7 +55,20 @@ type repl struct {
 	buffer     []string
 	bufferText string // cached strings.Join(buffer, "\n"), see appendOutput
 
-	quitting bool
+	quitting   bool
+	accessible bool
+
+	script    []string // startup inputs not yet collected, see script.go
+	scripting bool
+
+	rec     *recorder   // session transcript, see record.go
+	results *resultSink // per-evaluation JSON lines, see results.go
+
+	spin       spinner.Model
+	running    bool               // an input is between collect and its result
+	cancelEval context.CancelFunc // non-nil while evaluating, see pipeline.go
+	pending    []string           // inputs collected while running
+	dropped    int                // pending inputs to report as canceled
 
 	logQ chan []byte
 	log1 *sync.Once
@@ -80,6 +98,7 @@ func makeREPL(ctx context.Context, opts ...option[repl]) repl {
 		keys:   defaultKeyMap(),
 		hist:   loadHistory(pkg.CachePath(historyFile)),
 		screen: v,
+		spin:   spinner.New(spinner.WithSpinner(spinner.MiniDot)),
 		logQ:   make(chan []byte, logQueueSize),
 		log1:   new(sync.Once),
 	}
@@ -105,6 +124,25 @@ func withAST(ast lang.AST) option[repl] {
 	return func(l *repl) { l.ast = ast }
 }
 
+func withTheme(t theme) option[repl] {
+	return func(l *repl) { l.edit = l.edit.setTheme(t) }
+}
+
+// withAccessible enables screen-reader-friendly output: submitted input and
+// evaluation results are written as plain, labeled lines instead of styled
+// editor snapshots, and the alt-screen viewport is never entered.
+func withAccessible(accessible bool) option[repl] {
+	return func(l *repl) { l.accessible = accessible }
+}
+
+func withRecorder(rec *recorder) option[repl] {
+	return func(l *repl) { l.rec = rec }
+}
+
+func withResults(results *resultSink) option[repl] {
+	return func(l *repl) { l.results = results }
+}
+
 func (l repl) Init() tea.Cmd {
 	return tea.Batch(l.edit.Init(), tea.RequestBackgroundColor)
 }
@@ -159,6 +197,12 @@ func (l repl) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case evaluateMsg: // pipeline.go
 		return l.handleEvaluate(msg)
 
+	case evaluatedMsg: // pipeline.go
+		return l.handleEvaluated(msg)
+
+	case spinner.TickMsg: // output.go
+		return l.handleSpinnerTick(msg)
+
 	case resetMsg: // pipeline.go
 		return l.handleReset(msg)
 
@@ -236,15 +280,17 @@ func (l repl) View() tea.View {
 	return l.transcriptView(cursor)
 }
 
-func repLoop(ctx context.Context, ast lang.AST) error {
+func repLoop(ctx context.Context, ast lang.AST, opts ...option[repl]) error {
 	log.Debug(log.Attrs("history", pkg.CachePath(historyFile)))
 	l := makeREPL(
 		ctx,
 		withKeyMap(defaultKeyMap()),
 		withHistory(pkg.CachePath(historyFile)),
 		withAST(ast),
+		withOptions(opts...),
 	)
 
 	_, err := l.app.Run()
+	l.hist.close()
 	return err
 }
@@ -1,10 +1,15 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 )
@@ -95,6 +100,25 @@ func send(t *testing.T, m repl, msg tea.Msg) repl {
 	return pump(t, m, cmd)
 }
 
+// runEvaluate applies evaluateMsg{input}, runs the background evaluation it
+// starts, and applies the resulting evaluatedMsg.
+func runEvaluate(t *testing.T, m repl, input string) (repl, tea.Cmd) {
+	t.Helper()
+	m, cmd := applyMsg(t, m, evaluateMsg{input: input})
+	if cmd == nil {
+		t.Fatal("handleEvaluate(evaluateMsg) cmd = nil, want evaluation")
+	}
+	subs, ok := cmdSlice(cmd())
+	if !ok || len(subs) == 0 {
+		t.Fatal("handleEvaluate(evaluateMsg) cmd is not a batch")
+	}
+	msg, ok := subs[0]().(evaluatedMsg)
+	if !ok {
+		t.Fatal("handleEvaluate(evaluateMsg) first cmd does not yield evaluatedMsg")
+	}
+	return applyMsg(t, m, msg)
+}
+
 // newREPL builds a sized, focused repl ready to accept input. It runs Init for
 // coverage of the model lifecycle, applies an initial window size, and focuses
 // the editor directly to avoid the global logger side effects of the ready
@@ -382,9 +406,9 @@ func TestRepl_Update_CommitAndEvaluateInAltScreenBufferTranscript(t *testing.T)
 		t.Fatal("Update(commitMsg in alt-screen) cmd = nil, want evaluate command")
 	}
 
-	m, cmd = applyMsg(t, m, evaluateMsg{input: "hello"})
+	m, cmd = runEvaluate(t, m, "hello")
 	if cmd != nil {
-		t.Fatal("Update(evaluateMsg in alt-screen) cmd != nil, want nil when not quitting")
+		t.Fatal("Update(evaluatedMsg in alt-screen) cmd != nil, want nil when not quitting")
 	}
 	if got, want := len(m.buffer), 2; got != want {
 		t.Fatalf("output after evaluate has %d entries, want %d", got, want)
@@ -512,3 +536,174 @@ func TestRepl_Update_AltScreenKeepsFollowingBottomAfterFilledAndModeSwitch(t *te
 		t.Fatalf("alt-screen view after filled+switch = %q, want to contain %q", got, "tail-after-switch")
 	}
 }
+
+func TestRepl_Accessible_CaptureCommitsLabeledInput(t *testing.T) {
+	m := newREPL(t, withHistory(""), withAccessible(true))
+	_, cmd := applyMsg(t, m, captureMsg{input: "foo\nbar"})
+	steps, ok := cmdSlice(cmd())
+	if !ok || len(steps) != 2 {
+		t.Fatalf("handleCapture(captureMsg) = %#v, want sequence of reset and commit", cmd())
+	}
+	got, ok := steps[1]().(commitMsg)
+	if !ok {
+		t.Fatalf("second capture step = %#v, want commitMsg", steps[1]())
+	}
+	if want := "input: foo\ninput: bar"; got.view != want {
+		t.Fatalf("committed view = %q, want %q", got.view, want)
+	}
+}
+
+func TestRepl_Accessible_EvaluatePrintsLabeledResult(t *testing.T) {
+	m := newREPL(t, withHistory(""), withAccessible(true))
+	_, cmd := runEvaluate(t, m, "zqxv")
+	if cmd == nil {
+		t.Fatal("handleEvaluated(evaluatedMsg) cmd = nil, want println")
+	}
+	body, ok := printLineBody(cmd())
+	if !ok {
+		t.Fatalf("handleEvaluated(evaluatedMsg) = %#v, want tea.Println message", cmd())
+	}
+	if !strings.HasPrefix(body, "result: ") || !strings.Contains(body, "zqxv") {
+		t.Fatalf("printed result = %q, want labeled result containing %q", body, "zqxv")
+	}
+}
+
+func TestRepl_Accessible_IgnoresAltScreenToggle(t *testing.T) {
+	m := newREPL(t, withAccessible(true))
+	m, _ = applyMsg(t, m, altKey('s'))
+	if m.altScreen {
+		t.Fatal("altScreen = true after toggle in accessible mode, want false")
+	}
+	if got := m.edit.value(); got != "" {
+		t.Fatalf("edit value = %q after toggle, want key consumed", got)
+	}
+}
+
+// lastMsg runs cmd and returns the message of its final command, unwrapping
+// tea.Batch and tea.Sequence.
+func lastMsg(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("cmd = nil, want message")
+	}
+	msg := cmd()
+	if subs, ok := cmdSlice(msg); ok && len(subs) > 0 {
+		return lastMsg(t, subs[len(subs)-1])
+	}
+	return msg
+}
+
+// startEvaluate collects input and advances the cycle until its evaluation
+// is running, returning the command that yields the evaluation result.
+func startEvaluate(t *testing.T, m repl, input string) (repl, tea.Cmd) {
+	t.Helper()
+	m, cmd := applyMsg(t, m, collectMsg{input: input})
+	m, cmd = applyMsg(t, m, lastMsg(t, cmd)) // capture
+	m, cmd = applyMsg(t, m, lastMsg(t, cmd)) // commit
+	m, cmd = applyMsg(t, m, lastMsg(t, cmd)) // evaluate
+	if !m.evaluating() {
+		t.Fatalf("evaluating() = false after collecting %q, want true", input)
+	}
+	subs, ok := cmdSlice(cmd())
+	if !ok || len(subs) == 0 {
+		t.Fatal("handleEvaluate(evaluateMsg) cmd is not a batch")
+	}
+	return m, subs[0]
+}
+
+func TestRepl_Evaluate_QueuesInputUntilResult(t *testing.T) {
+	m := newREPL(t, withHistory(""))
+	m, first := startEvaluate(t, m, "first")
+	if got := visible(m.View().Content); !strings.Contains(got, "evaluating") {
+		t.Fatalf("View().Content = %q, want status line", got)
+	}
+
+	m, cmd := applyMsg(t, m, collectMsg{input: "second"})
+	if _, ok := lastMsg(t, cmd).(resetMsg); !ok {
+		t.Fatal("handleCollect(collectMsg) while busy, want only an editor reset")
+	}
+	if got, want := m.pending, []string{"second"}; !slices.Equal(got, want) {
+		t.Fatalf("pending = %q, want %q", got, want)
+	}
+
+	// The queued input is echoed only after the first result is printed.
+	m, cmd = applyMsg(t, m, first())
+	if m.evaluating() || len(m.pending) != 0 {
+		t.Fatalf("after first result: evaluating = %v, pending = %q, want idle and empty",
+			m.evaluating(), m.pending)
+	}
+	subs, ok := cmdSlice(cmd())
+	if !ok || len(subs) != 2 {
+		t.Fatal("handleEvaluated(evaluatedMsg) cmd is not a sequence of result and next input")
+	}
+	if body, _ := printLineBody(subs[0]()); !strings.Contains(body, "first") {
+		t.Fatalf("printed result = %q, want result of first input", body)
+	}
+	got, ok := subs[1]().(captureMsg)
+	if !ok || got.input != "second" || !got.detached {
+		t.Fatalf("next command = %#v, want detached captureMsg for queued input", got)
+	}
+	if _, cmd = applyMsg(t, m, got); cmd == nil {
+		t.Fatal("handleCapture(captureMsg) cmd = nil, want commit")
+	}
+	if c, ok := cmd().(commitMsg); !ok || c.text != "second" {
+		t.Fatalf("handleCapture(detached) = %#v, want commitMsg without reset", c)
+	}
+}
+
+func TestRepl_Evaluate_QuitKeyCancels(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := makeRecorder(&buf, recordMarkdown, time.Now())
+	if err != nil {
+		t.Fatalf("makeRecorder() error = %v", err)
+	}
+	m := newREPL(t, withHistory(""), withRecorder(rec))
+	ast := m.ast
+	m, start := startEvaluate(t, m, "first")
+	m, _ = applyMsg(t, m, collectMsg{input: "second"})
+
+	m, cmd := applyMsg(t, m, ctrlKey('c'))
+	if cmd != nil {
+		t.Fatal("Update(ctrl+c) while evaluating cmd != nil, want key consumed")
+	}
+
+	msg, ok := start().(evaluatedMsg)
+	if !ok || !errors.Is(msg.err, context.Canceled) {
+		t.Fatalf("evaluation result = %#v, want context.Canceled", msg)
+	}
+	m, cmd = applyMsg(t, m, msg)
+	if m.evaluating() {
+		t.Fatal("evaluating() = true after canceled result, want false")
+	}
+	if !reflect.DeepEqual(m.ast, ast) {
+		t.Fatal("AST changed after canceled evaluation, want unchanged")
+	}
+	subs, _ := cmdSlice(cmd())
+	if body, _ := printLineBody(subs[0]()); body != "evaluation canceled" {
+		t.Fatalf("printed = %q, want cancellation notice", body)
+	}
+
+	// The dropped input is echoed and reported as canceled, never evaluated.
+	m, cmd = applyMsg(t, m, subs[1]())       // capture
+	m, cmd = applyMsg(t, m, lastMsg(t, cmd)) // commit
+	subs, _ = cmdSlice(cmd())
+	if body, _ := printLineBody(subs[0]()); !strings.Contains(body, "second") {
+		t.Fatalf("echoed = %q, want dropped input", body)
+	}
+	m, cmd = applyMsg(t, m, lastMsg(t, cmd)) // evaluate
+	msg, ok = cmd().(evaluatedMsg)
+	if !ok || msg.input != "second" || !errors.Is(msg.err, context.Canceled) {
+		t.Fatalf("dropped input result = %#v, want context.Canceled", msg)
+	}
+	m, cmd = applyMsg(t, m, msg)
+	if body, _ := printLineBody(lastMsg(t, cmd)); body != "evaluation canceled" {
+		t.Fatalf("printed = %q, want cancellation notice", body)
+	}
+	if m.running || m.dropped != 0 || len(m.pending) != 0 {
+		t.Fatalf("after drop: running = %v, dropped = %d, pending = %q, want idle",
+			m.running, m.dropped, m.pending)
+	}
+	if got := strings.Count(buf.String(), "evaluation canceled"); got != 2 {
+		t.Fatalf("transcript has %d cancellation notices, want 2:\n%s", got, buf.String())
+	}
+}
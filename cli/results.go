@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ardnew/aenv/log"
+)
+
+// resultSink appends one JSON object per evaluation to a results file, so that
+// sessions can be analyzed or compared against each other later. It is shared
+// by pointer among copies of the [repl] model.
+//
+// Like [recorder], write errors are logged once, after which the sink stops.
+type resultSink struct {
+	enc    *json.Encoder
+	start  time.Time
+	seq    int
+	failed bool
+}
+
+// result is the JSON line written for each evaluation. Session is the time the
+// session started, which distinguishes sessions appended to the same file;
+// Seq numbers the evaluations within a session. Result holds the evaluated
+// AST's JSON verbatim; it is omitted if the evaluation failed or was canceled.
+type result struct {
+	Session  time.Time       `json:"session"`
+	Time     time.Time       `json:"time"`
+	Seq      int             `json:"seq"`
+	Input    string          `json:"input"`
+	Result   json.RawMessage `json:"result,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Canceled bool            `json:"canceled,omitempty"`
+	Elapsed  time.Duration   `json:"elapsed"` // nanoseconds
+}
+
+// openResults opens (or creates) the results file at path for appending, so
+// that repeated sessions accumulate in one file.
+func openResults(path string) (*resultSink, io.Closer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Debug(log.Attrs("path", path), "results out")
+	return makeResultSink(file, time.Now()), file, nil
+}
+
+// makeResultSink returns a resultSink writing to w for a session that started
+// at start.
+func makeResultSink(w io.Writer, start time.Time) *resultSink {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &resultSink{enc: enc, start: start}
+}
+
+// write appends the outcome of an evaluation to the results file.
+func (s *resultSink) write(msg evaluatedMsg, canceled bool) {
+	if s == nil || s.failed {
+		return
+	}
+	s.seq++
+	r := result{
+		Session:  s.start,
+		Time:     time.Now(),
+		Seq:      s.seq,
+		Input:    msg.input,
+		Canceled: canceled,
+		Elapsed:  msg.elapsed,
+	}
+	switch {
+	case msg.err != nil:
+		r.Error = msg.err.Error()
+	case json.Valid([]byte(msg.output)):
+		r.Result = json.RawMessage(msg.output)
+	default:
+		r.Result, _ = json.Marshal(msg.output)
+	}
+	if err := s.enc.Encode(r); err != nil {
+		s.failed = true
+		log.Warn(log.Attrs("error", err), "results out stopped")
+	}
+}
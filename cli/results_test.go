@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResultSink_Write_EncodesOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      evaluatedMsg
+		canceled bool
+		want     string // JSON of the result, error and canceled fields
+	}{
+		{
+			name: "json result",
+			msg:  evaluatedMsg{input: "a", output: `{"src":"a"}`},
+			want: `{"result":{"src":"a"}}`,
+		},
+		{
+			name: "text result",
+			msg:  evaluatedMsg{input: "a", output: "plain"},
+			want: `{"result":"plain"}`,
+		},
+		{
+			name: "error",
+			msg:  evaluatedMsg{input: "a", err: errors.New("boom")},
+			want: `{"error":"boom"}`,
+		},
+		{
+			name:     "canceled",
+			msg:      evaluatedMsg{input: "a", err: context.Canceled},
+			canceled: true,
+			want:     `{"error":"context canceled","canceled":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			makeResultSink(&buf, time.Now()).write(tt.msg, tt.canceled)
+
+			var got result
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal %q: %v", buf.String(), err)
+			}
+			if got.Input != tt.msg.input || got.Seq != 1 {
+				t.Fatalf("input, seq = %q, %d, want %q, 1", got.Input, got.Seq, tt.msg.input)
+			}
+			fields, _ := json.Marshal(struct {
+				Result   json.RawMessage `json:"result,omitempty"`
+				Error    string          `json:"error,omitempty"`
+				Canceled bool            `json:"canceled,omitempty"`
+			}{got.Result, got.Error, got.Canceled})
+			if string(fields) != tt.want {
+				t.Fatalf("fields = %s, want %s", fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepl_Results_AppendsEachEvaluation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results, closer, err := openResults(path)
+	if err != nil {
+		t.Fatalf("openResults() error = %v", err)
+	}
+	m := newREPL(t, withHistory(""), withResults(results))
+	m, _ = runEvaluate(t, m, "one")
+	_, _ = runEvaluate(t, m, "two")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	var lines []result
+	sin := bufio.NewScanner(file)
+	for sin.Scan() {
+		var r result
+		if err := json.Unmarshal(sin.Bytes(), &r); err != nil {
+			t.Fatalf("unmarshal %q: %v", sin.Text(), err)
+		}
+		lines = append(lines, r)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("results file has %d lines, want existing line plus 2", len(lines))
+	}
+	for i, input := range []string{"one", "two"} {
+		r := lines[i+1]
+		if r.Input != input || r.Seq != i+1 || len(r.Result) == 0 {
+			t.Fatalf("line %d = %+v, want input %q seq %d with result", i+1, r, input, i+1)
+		}
+		if r.Session.IsZero() || !r.Session.Equal(lines[1].Session) {
+			t.Fatalf("line %d session = %v, want the session's start time", i+1, r.Session)
+		}
+		if r.Time.IsZero() || r.Elapsed < 0 || r.Elapsed > time.Minute {
+			t.Fatalf("line %d metadata = %v, %v, want timestamp and elapsed", i+1, r.Time, r.Elapsed)
+		}
+	}
+}
+
+func TestResultSink_Write_DistinguishesSessions(t *testing.T) {
+	var buf bytes.Buffer
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	second := first.Add(time.Hour)
+	for _, start := range []time.Time{first, second} {
+		makeResultSink(&buf, start).write(evaluatedMsg{input: "a", output: "{}"}, false)
+	}
+
+	dec := json.NewDecoder(&buf)
+	for _, want := range []time.Time{first, second} {
+		var got result
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if !got.Session.Equal(want) || got.Seq != 1 {
+			t.Fatalf("session, seq = %v, %d, want %v, 1", got.Session, got.Seq, want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/ardnew/aenv/log"
+)
+
+// Startup scripts are inputs submitted to the REPL before interactive input.
+//
+// Each entry is run through the same collect/capture/commit/evaluate cycle as
+// typed input (see pipeline.go), one at a time: the next entry is collected
+// only once the previous entry's evaluation completes, so outputs appear in
+// script order.
+
+// splitScript splits a semicolon-separated script into its non-blank inputs.
+func splitScript(script string) []string {
+	var entries []string
+	for entry := range strings.SplitSeq(script, ";") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// readScriptFile returns the non-blank lines of the file at path, one input per
+// line.
+func readScriptFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []string
+	sin := bufio.NewScanner(file)
+	for sin.Scan() {
+		if entry := strings.TrimSpace(sin.Text()); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, sin.Err()
+}
+
+// withScript queues entries to be evaluated once the REPL is ready.
+func withScript(entries ...string) option[repl] {
+	return func(l *repl) { l.script = append(l.script, entries...) }
+}
+
+// startScript begins running the startup script, unless it is empty or has
+// already started (readyMsg can fire more than once).
+func (l repl) startScript() (repl, tea.Cmd) {
+	if l.scripting || len(l.script) == 0 {
+		return l, nil
+	}
+	log.Debug(log.Attrs("count", len(l.script)), "startup script")
+	l.scripting = true
+	return l.nextScript()
+}
+
+// nextScript collects the next startup script entry, if any remain.
+func (l repl) nextScript() (repl, tea.Cmd) {
+	if !l.scripting {
+		return l, nil
+	}
+	if len(l.script) == 0 {
+		log.Trace(log.Attrs("reason", "done"), "startup script")
+		l.scripting = false
+		return l, nil
+	}
+	entry := l.script[0]
+	l.script = l.script[1:]
+	log.Trace(log.Attrs("len", len(entry), "remaining", len(l.script)), "startup script entry")
+	return l, collectScript(entry)
+}
+
+// stopScript discards the remaining startup script entries, if any, so that
+// canceling an evaluation aborts the script rather than moving to its next
+// entry.
+func (l repl) stopScript() repl {
+	if l.scripting {
+		log.Debug(log.Attrs("remaining", len(l.script)), "startup script stopped")
+	}
+	l.script, l.scripting = nil, false
+	return l
+}
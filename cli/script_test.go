@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSplitScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{name: "empty", script: "", want: nil},
+		{name: "single", script: "foo", want: []string{"foo"}},
+		{name: "trims and skips blanks", script: " foo ;; bar ; ", want: []string{"foo", "bar"}},
+		{name: "keeps newlines", script: "a\nb; c", want: []string{"a\nb", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitScript(tt.script); !slices.Equal(got, tt.want) {
+				t.Fatalf("splitScript(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadScriptFile_OneInputPerLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rc")
+	if err := os.WriteFile(path, []byte("first\n\n  second  \nthird"), 0o644); err != nil {
+		t.Fatalf("write rc: %v", err)
+	}
+	got, err := readScriptFile(path)
+	if err != nil {
+		t.Fatalf("readScriptFile() error = %v", err)
+	}
+	if want := []string{"first", "second", "third"}; !slices.Equal(got, want) {
+		t.Fatalf("readScriptFile() = %q, want %q", got, want)
+	}
+}
+
+func TestEval_Script_RCBeforeExec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rc")
+	if err := os.WriteFile(path, []byte("rc-1\nrc-2\n"), 0o644); err != nil {
+		t.Fatalf("write rc: %v", err)
+	}
+	got, err := Eval{RC: path, Exec: "exec-1; exec-2"}.script()
+	if err != nil {
+		t.Fatalf("script() error = %v", err)
+	}
+	if want := []string{"rc-1", "rc-2", "exec-1", "exec-2"}; !slices.Equal(got, want) {
+		t.Fatalf("script() = %q, want %q", got, want)
+	}
+
+	if _, err := (Eval{RC: filepath.Join(t.TempDir(), "missing")}).script(); err == nil {
+		t.Fatal("script() with missing rc error = nil")
+	}
+}
+
+func TestRepl_Script_EvaluatesEntriesInOrderOnce(t *testing.T) {
+	restoreDefaultLogger(t)
+	var buf bytes.Buffer
+	rec, err := makeRecorder(&buf, recordMarkdown, time.Now())
+	if err != nil {
+		t.Fatalf("makeRecorder() error = %v", err)
+	}
+	m := newREPL(t, withHistory(""), withRecorder(rec), withScript("one", "two", "three"))
+	m = typeKey(t, m, 'd')
+
+	m = send(t, m, readyMsg{})
+	got := regexp.MustCompile("(?m)^```aenv\n(.*)$").FindAllStringSubmatch(buf.String(), -1)
+	var inputs []string
+	for _, g := range got {
+		inputs = append(inputs, g[1])
+	}
+	if want := []string{"one", "two", "three"}; !slices.Equal(inputs, want) {
+		t.Fatalf("evaluated inputs = %q, want %q", inputs, want)
+	}
+	if m.scripting || len(m.script) != 0 {
+		t.Fatalf("script state = (%v, %q) after run, want finished", m.scripting, m.script)
+	}
+	if len(m.hist.entries) != 0 {
+		t.Fatalf("history = %q after script, want script entries left out", m.hist.entries)
+	}
+	if got := m.edit.value(); got != "d" {
+		t.Fatalf("edit value = %q after script, want draft %q kept", got, "d")
+	}
+
+	// A later readyMsg (e.g., on resize) must not re-run the script.
+	n := buf.Len()
+	m = send(t, m, readyMsg{})
+	if buf.Len() != n {
+		t.Fatalf("transcript grew after second ready:\n%s", buf.String()[n:])
+	}
+}
+
+func TestRepl_Script_QuitKeyCancelsRemainingEntries(t *testing.T) {
+	m := newREPL(t, withHistory(""), withScript("first", "second", "third"))
+	m, cmd := m.startScript()
+	if got, ok := cmd().(collectMsg); !ok || got.input != "first" {
+		t.Fatalf("startScript() = %#v, want collectMsg for first entry", got)
+	}
+
+	m, start := applyMsg(t, m, evaluateMsg{input: "first"})
+	m, _ = applyMsg(t, m, ctrlKey('c'))
+	if m.scripting || len(m.script) != 0 {
+		t.Fatalf("after cancel: scripting = %v, script = %q, want stopped",
+			m.scripting, m.script)
+	}
+
+	subs, _ := cmdSlice(start())
+	m, cmd = applyMsg(t, m, subs[0]())
+	if m.evaluating() {
+		t.Fatal("evaluating() = true after canceled result, want false")
+	}
+	if body, ok := printLineBody(cmd()); !ok || body != "evaluation canceled" {
+		t.Fatalf("handleEvaluated() = %q, want only the cancellation notice", body)
+	}
+}
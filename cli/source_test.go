@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ardnew/aenv/exit"
+	"github.com/ardnew/aenv/lang"
 	"github.com/ardnew/aenv/log"
 	"github.com/ardnew/aenv/pkg"
 )
@@ -153,6 +155,20 @@ func TestWithSources_LogsOnePerSource(t *testing.T) {
 	}
 }
 
+func TestWithSources_RejectsNewerLangPragma(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newer.aenv")
+	if err := os.WriteFile(path, []byte("#lang aenv/2\na : 1\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile(%q) error = %v", path, err)
+	}
+
+	var e Eval
+	err := withSources([]string{path}, &e)
+	if !errors.Is(err, lang.ErrUnsupportedVersion) {
+		t.Fatalf("withSources() error = %v, want %v", err, lang.ErrUnsupportedVersion)
+	}
+	assertExitCode(t, err, exit.Data)
+}
+
 func chdir(t *testing.T, dir string) {
 	t.Helper()
 	prev, err := os.Getwd()
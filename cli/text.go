@@ -41,3 +41,19 @@ func splitInput(value string) []string {
 	}
 	return strings.Split(value, "\n")
 }
+
+// Labels prefixed to each line of accessible output (see withAccessible).
+const (
+	inputLabel  = "input"
+	resultLabel = "result"
+)
+
+// labelLines prefixes each newline-delimited line of text with label, so that
+// line-oriented readers (e.g., screen readers) announce what each line is.
+func labelLines(label, text string) string {
+	lines := splitInput(strings.TrimRight(text, "\r\n"))
+	for i, line := range lines {
+		lines[i] = label + ": " + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -41,6 +41,7 @@ type TextEdit struct {
 	area AreaEdit
 
 	style struct {
+		theme  theme
 		isDark bool
 	}
 
@@ -12,7 +12,9 @@ import (
 // (colors/styles), separate from textedit.go's mode-dispatch/model logic.
 
 type editStyle struct {
-	prompt rune
+	prompt    rune
+	colorless bool // no colors at all, including the editors' default cursor
+
 	editor lipgloss.Style
 	border lipgloss.Style
 	cursor lipgloss.Style
@@ -20,40 +22,98 @@ type editStyle struct {
 	record lipgloss.Style
 }
 
-func defaultStyle(isDark bool) editStyle {
+// theme names a built-in color palette applied to all REPL output.
+type theme string
+
+const (
+	themeAuto      theme = "auto"      // follow the terminal background
+	themeDark      theme = "dark"      // dark palette regardless of background
+	themeLight     theme = "light"     // light palette regardless of background
+	themeSolarized theme = "solarized" // solarized, following the background
+	themeNoColor   theme = "no-color"  // no foreground or background colors
+)
+
+// palette holds the colors of a theme resolved for one terminal background.
+//
+// A nil color leaves the corresponding text unstyled.
+type palette struct {
+	editorText, editorBackground color.Color
+	cursorText, cursorBackground color.Color
+	dimmedText, recordText       color.Color
+}
+
+// palette resolves the receiver's colors for a terminal with a dark (isDark)
+// or light background. Unrecognized themes resolve like [themeAuto].
+func (t theme) palette(isDark bool) palette {
+	switch t {
+	case themeDark:
+		isDark = true
+	case themeLight:
+		isDark = false
+	case themeNoColor:
+		return palette{}
+	}
+
 	auto := lipgloss.LightDark(isDark)
 	autoColor := func(light, dark string) color.Color {
 		return auto(lipgloss.Color(light), lipgloss.Color(dark))
 	}
 
-	promptSymbol := '❯'
+	if t == themeSolarized {
+		return palette{
+			editorText:       autoColor("#586e75", "#93a1a1"),
+			editorBackground: autoColor("#eee8d5", "#073642"),
+			cursorText:       autoColor("#586e75", "#93a1a1"),
+			cursorBackground: autoColor("#fdf6e3", "#002b36"),
+			dimmedText:       autoColor("#93a1a1", "#586e75"),
+			recordText:       autoColor("#657b83", "#839496"),
+		}
+	}
 
-	editorText := autoColor("#1f4f58", "#addfda")
-	editorBackground := autoColor("#edf2f6", "#212324")
+	return palette{
+		editorText:       autoColor("#1f4f58", "#addfda"),
+		editorBackground: autoColor("#edf2f6", "#212324"),
+		cursorText:       autoColor("#1f4f58", "#addfda"),
+		cursorBackground: autoColor("#f4f8fb", "#24292e"),
+		dimmedText:       autoColor("#8f97a3", "#505050"),
+		recordText:       autoColor("#778391", "#a6b3bf"),
+	}
+}
 
-	cursorText := autoColor("#1f4f58", "#addfda")
-	cursorBackground := autoColor("#f4f8fb", "#24292e")
+func defaultStyle(isDark bool) editStyle {
+	return themeStyle(themeAuto, isDark)
+}
 
-	dimmedText := autoColor("#8f97a3", "#505050")
+// themeStyle returns the editor styles of theme t for a terminal with a dark
+// (isDark) or light background.
+func themeStyle(t theme, isDark bool) editStyle {
+	pal := t.palette(isDark)
+	orNone := func(c color.Color) color.Color {
+		if c == nil {
+			return lipgloss.NoColor{}
+		}
+		return c
+	}
 
-	recordText := autoColor("#778391", "#a6b3bf")
+	promptSymbol := '❯'
 
 	return editStyle{
-		prompt: promptSymbol,
+		prompt:    promptSymbol,
+		colorless: pal == (palette{}),
 		editor: lipgloss.NewStyle().
-			Foreground(editorText).
-			Background(editorBackground),
+			Foreground(orNone(pal.editorText)).
+			Background(orNone(pal.editorBackground)),
 		border: lipgloss.NewStyle().
 			Border(lipgloss.ThickBorder(), false, false, false, true).
-			BorderForeground(cursorText),
+			BorderForeground(orNone(pal.cursorText)),
 		cursor: lipgloss.NewStyle().
-			Foreground(cursorText).
-			Background(cursorBackground),
+			Foreground(orNone(pal.cursorText)).
+			Background(orNone(pal.cursorBackground)),
 		dimmed: lipgloss.NewStyle().
-			Foreground(dimmedText).
+			Foreground(orNone(pal.dimmedText)).
 			UnsetBackground(),
 		record: lipgloss.NewStyle().
-			Foreground(recordText).
+			Foreground(orNone(pal.recordText)).
 			UnsetBackground(),
 	}
 }
@@ -61,14 +121,21 @@ func defaultStyle(isDark bool) editStyle {
 func (e TextEdit) setStyle(isDark bool) TextEdit {
 	e.style.isDark = isDark
 
-	e.line = e.line.setStyle(isDark)
-	e.area = e.area.setStyle(isDark)
+	e.line = e.line.setStyle(e.style.theme, isDark)
+	e.area = e.area.setStyle(e.style.theme, isDark)
 	return e
 }
 
-func (e LineEdit) setStyle(isDark bool) LineEdit {
+// setTheme selects the theme used by subsequent calls to setStyle and
+// restyles both editors for the current background.
+func (e TextEdit) setTheme(t theme) TextEdit {
+	e.style.theme = t
+	return e.setStyle(e.style.isDark)
+}
+
+func (e LineEdit) setStyle(t theme, isDark bool) LineEdit {
 	st := textinput.DefaultStyles(isDark)
-	et := defaultStyle(isDark)
+	et := themeStyle(t, isDark)
 
 	st.Focused.Text = et.cursor.Inherit(et.editor)
 	st.Focused.Prompt = et.cursor.Inherit(et.border)
@@ -80,13 +147,17 @@ func (e LineEdit) setStyle(isDark bool) LineEdit {
 	st.Blurred.Placeholder = et.record
 	st.Blurred.Suggestion = et.record
 
+	if et.colorless {
+		st.Cursor.Color = nil
+	}
+
 	e.SetStyles(st)
 	return e.setPromptSymbol(et.prompt)
 }
 
-func (e AreaEdit) setStyle(isDark bool) AreaEdit {
+func (e AreaEdit) setStyle(t theme, isDark bool) AreaEdit {
 	st := textarea.DefaultStyles(isDark)
-	et := defaultStyle(isDark)
+	et := themeStyle(t, isDark)
 
 	st.Focused.Base = et.editor.Inherit(et.border)
 	st.Focused.Text = et.editor
@@ -106,6 +177,10 @@ func (e AreaEdit) setStyle(isDark bool) AreaEdit {
 	st.Blurred.Placeholder = et.record
 	st.Blurred.Prompt = et.record
 
+	if et.colorless {
+		st.Cursor.Color = nil
+	}
+
 	e.SetStyles(st)
 	return e
 }
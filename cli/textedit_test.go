@@ -148,3 +148,64 @@ func TestTextEdit_CursorState_AreaLineAreaRoundTripPreservesFormat(t *testing.T)
 		t.Fatalf("area column after round trip = %d, want %d", got, 1)
 	}
 }
+
+func TestTheme_Palette_ResolvesBackground(t *testing.T) {
+	tests := []struct {
+		name   string
+		theme  theme
+		isDark bool
+		want   palette
+	}{
+		{name: "auto dark", theme: themeAuto, isDark: true, want: themeAuto.palette(true)},
+		{name: "auto light", theme: themeAuto, isDark: false, want: themeAuto.palette(false)},
+		{name: "dark on light", theme: themeDark, isDark: false, want: themeAuto.palette(true)},
+		{name: "light on dark", theme: themeLight, isDark: true, want: themeAuto.palette(false)},
+		{name: "unknown", theme: theme("bogus"), isDark: true, want: themeAuto.palette(true)},
+		{name: "no-color", theme: themeNoColor, isDark: true, want: palette{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.theme.palette(tt.isDark); got != tt.want {
+				t.Fatalf("%q.palette(%v) = %+v, want %+v", tt.theme, tt.isDark, got, tt.want)
+			}
+		})
+	}
+	if themeSolarized.palette(true) == themeAuto.palette(true) {
+		t.Fatal("solarized palette matches auto palette, want distinct colors")
+	}
+}
+
+func TestTextEdit_SetTheme_KeepsThemeAcrossRestyle(t *testing.T) {
+	e := makeTextEdit().setTheme(themeSolarized)
+	e = e.setStyle(false)
+	if got := e.style.theme; got != themeSolarized {
+		t.Fatalf("theme after setStyle = %q, want %q", got, themeSolarized)
+	}
+	if e.style.isDark {
+		t.Fatal("isDark after setStyle(false) = true, want false")
+	}
+}
+
+func TestTextEdit_SetTheme_NoColorClearsCursorColor(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme theme
+		clear bool
+	}{
+		{name: "auto", theme: themeAuto},
+		{name: "no-color", theme: themeNoColor, clear: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := makeTextEdit().setTheme(tt.theme)
+			line, area := e.line.Styles().Cursor.Color, e.area.Styles().Cursor.Color
+			if got := line == nil; got != tt.clear {
+				t.Fatalf("line cursor color = %v, want cleared %v", line, tt.clear)
+			}
+			if got := area == nil; got != tt.clear {
+				t.Fatalf("area cursor color = %v, want cleared %v", area, tt.clear)
+			}
+			_ = e.View() // renders without a cursor color
+		})
+	}
+}
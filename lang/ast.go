@@ -5,6 +5,7 @@ package lang
 import (
 	"bytes"
 	"encoding/json/v2"
+	"errors"
 	"io"
 	"unicode/utf8"
 
@@ -17,41 +18,122 @@ func (b Buffer) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(b))
 }
 
+// bom is the UTF-8 encoding of the byte order mark U+FEFF, which some editors
+// (notably on Windows) prepend to text files. It is stripped from the start of
+// each source so that it never shifts the columns of the first line.
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// skipBOM returns a reader of r without its leading byte order mark, if any,
+// and the number of bytes skipped.
+//
+// It reads no further into r than the length of the mark, so that a limit
+// applied to the returned reader counts source bytes only.
+func skipBOM(r io.Reader) (io.Reader, int64, error) {
+	head := make([]byte, len(bom))
+	n, err := io.ReadFull(r, head)
+	if bytes.Equal(head, bom) {
+		return r, int64(n), nil
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	return io.MultiReader(bytes.NewReader(head[:n]), r), 0, err
+}
+
 // AST represents the abstract syntax tree of a source.
 type AST struct {
 	B   Buffer `json:"src"`
 	Pos Pos    `json:"pos"`
+
+	// Sources records the file each range of B was read from, if any.
+	Sources []Source `json:"sources,omitempty"`
+
+	maxInputSize int64 // see WithMaxInputSize
 }
 
 func (a *AST) Write(b []byte) (int, error) {
 	log.Trace(log.Attrs("pos", a.Pos, "len", len(b)))
 
+	src := bytes.TrimPrefix(b, bom) // Write replaces the stream from its start.
+	if err := a.checkInputSize(int64(len(src))); err != nil {
+		log.Debug(log.Attrs("error", err))
+		return 0, err
+	}
+	if err := checkPragma(src); err != nil {
+		log.Debug(log.Attrs("error", err))
+		return 0, err
+	}
+
 	// a.scan(b)
-	a.B = make([]byte, len(b))
-	copy(a.B, b)
+	a.B = make([]byte, len(src))
+	copy(a.B, src)
 	log.Debug(log.Attrs("pos", a.Pos))
 	return len(b), nil
 }
 
 // parse reads source from r and appends its position state to the AST.
 func (a *AST) parse(r io.Reader) (int64, error) {
-	log.Trace(log.Attrs("pos", a.Pos))
+	return a.parseSource(r, len(a.B) == 0)
+}
+
+// parseSource is like parse, but if start is true, r is read as the start of
+// a new source: a leading byte order mark is skipped and the #lang pragma, if
+// any, is checked before the source is buffered.
+func (a *AST) parseSource(r io.Reader, start bool) (int64, error) {
+	log.Trace(log.Attrs("pos", a.Pos, "start", start))
+	var skipped int64
+	if start {
+		// Skip the mark before limiting r, since it is not part of the source.
+		var err error
+		if r, skipped, err = skipBOM(r); err != nil {
+			return 0, err
+		}
+	}
+	if a.maxInputSize > 0 {
+		// Read at most one byte past the limit, which is enough to detect
+		// oversized input without buffering all of it.
+		r = io.LimitReader(r, max(0, a.maxInputSize-int64(len(a.B)))+1)
+	}
 	b, err := io.ReadAll(r)
-	n := a.scan(b)
+	if serr := a.checkInputSize(int64(len(a.B) + len(b))); serr != nil {
+		log.Debug(log.Attrs("pos", a.Pos, "error", serr))
+		return 0, serr
+	}
+	if start {
+		if perr := checkPragma(b); perr != nil {
+			log.Debug(log.Attrs("pos", a.Pos, "error", perr))
+			return 0, perr
+		}
+	}
+	a.scan(b)
 	log.Debug(log.Attrs("pos", a.Pos, "error", err))
-	return n, err
+	return skipped + int64(len(b)), err
+}
+
+// checkInputSize returns an error if size exceeds the configured maximum input
+// size (see WithMaxInputSize).
+func (a *AST) checkInputSize(size int64) error {
+	if a.maxInputSize > 0 && size > a.maxInputSize {
+		return errf(ErrInputTooLarge, "%d bytes exceeds limit of %d bytes",
+			size, a.maxInputSize)
+	}
+	return nil
 }
 
+// scan appends b to the buffered source and advances the position past it.
+//
+// The position is valid (line=column=1) after any scan, even of empty input,
+// so that a scanned AST never reports the invalid zero Pos.
 func (a *AST) scan(b []byte) int64 {
 	n := int64(len(b))
 	a.B = append(a.B, b...)
+	if a.Pos.Line == 0 {
+		a.Pos.Line = 1
+	}
+	if a.Pos.Column == 0 {
+		a.Pos.Column = 1
+	}
 	if n != 0 {
-		if a.Pos.Line == 0 {
-			a.Pos.Line = 1
-		}
-		if a.Pos.Column == 0 {    
-			a.Pos.Column = 1
-		}
 		a.Pos.Offset += n
 		if lastLine := bytes.LastIndexByte(b, '\n'); lastLine >= 0 {
 			a.Pos.Line += int64(bytes.Count(b, []byte{'\n'}))
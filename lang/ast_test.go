@@ -1,6 +1,7 @@
 package lang
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -44,3 +45,163 @@ func TestAST_ParseAdvancesPositionIncrementally(t *testing.T) {
 		t.Fatalf("second parse pos = %+v, want %+v", got, want)
 	}
 }
+
+func TestAST_Write_MaxInputSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int64
+		input   string
+		wantErr bool
+	}{
+		{name: "unlimited", limit: 0, input: "abcdef"},
+		{name: "at limit", limit: 6, input: "abcdef"},
+		{name: "over limit", limit: 5, input: "abcdef", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(WithMaxInputSize(tt.limit))
+			n, err := a.Write([]byte(tt.input))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInputTooLarge) {
+					t.Fatalf("Write() error = %v, want %v", err, ErrInputTooLarge)
+				}
+				if n != 0 || len(a.B) != 0 {
+					t.Fatalf("Write() buffered %d bytes (n=%d), want none", len(a.B), n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := string(a.B); got != tt.input {
+				t.Fatalf("buffer = %q, want %q", got, tt.input)
+			}
+		})
+	}
+}
+
+func TestAST_Parse_MaxInputSizeStopsReading(t *testing.T) {
+	a := New(WithMaxInputSize(8))
+	if _, err := a.parse(strings.NewReader("1234")); err != nil {
+		t.Fatalf("parse first chunk: %v", err)
+	}
+
+	r := strings.NewReader(strings.Repeat("x", 1<<20))
+	_, err := a.parse(r)
+	if !errors.Is(err, ErrInputTooLarge) {
+		t.Fatalf("parse() error = %v, want %v", err, ErrInputTooLarge)
+	}
+	if got, want := r.Len(), 1<<20-5; got != want {
+		t.Fatalf("unread bytes = %d, want %d", got, want)
+	}
+	if got := string(a.B); got != "1234" {
+		t.Fatalf("buffer = %q, want %q", got, "1234")
+	}
+}
+
+func TestAST_MaxInputSize_ExcludesBOM(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "at limit", input: "\uFEFF12345678"},
+		{name: "over limit", input: "\uFEFF1234567890", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := map[string]func(a *AST) error{
+				"parse": func(a *AST) error {
+					_, err := a.parse(strings.NewReader(tt.input))
+					return err
+				},
+				"Write": func(a *AST) error {
+					_, err := a.Write([]byte(tt.input))
+					return err
+				},
+			}
+			for name, entry := range entries {
+				a := New(WithMaxInputSize(8))
+				err := entry(a)
+				if tt.wantErr {
+					if !errors.Is(err, ErrInputTooLarge) {
+						t.Fatalf("%s() error = %v, want %v", name, err, ErrInputTooLarge)
+					}
+					if len(a.B) != 0 {
+						t.Fatalf("%s() buffered %q, want nothing", name, a.B)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s() error = %v", name, err)
+				}
+				if got, want := string(a.B), "12345678"; got != want {
+					t.Fatalf("%s() buffer = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAST_Parse_BOMAndCRLF(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		src    string
+		pos    Pos
+	}{
+		{
+			name:   "bom stripped at start",
+			chunks: []string{"\uFEFFab"},
+			src:    "ab",
+			pos:    Pos{Offset: 2, Line: 1, Column: 3},
+		},
+		{
+			name:   "bom kept mid-stream",
+			chunks: []string{"a", "\uFEFFb"},
+			src:    "a\uFEFFb",
+			pos:    Pos{Offset: 5, Line: 1, Column: 4},
+		},
+		{
+			name:   "crlf line endings",
+			chunks: []string{"\uFEFFa\r\nbc\r\n", "d"},
+			src:    "a\r\nbc\r\nd",
+			pos:    Pos{Offset: 8, Line: 3, Column: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a AST
+			for _, chunk := range tt.chunks {
+				n, err := a.parse(strings.NewReader(chunk))
+				if err != nil {
+					t.Fatalf("parse(%q): %v", chunk, err)
+				}
+				if want := int64(len(chunk)); n != want {
+					t.Fatalf("parse(%q) bytes = %d, want %d", chunk, n, want)
+				}
+			}
+			if got := string(a.B); got != tt.src {
+				t.Fatalf("source = %q, want %q", got, tt.src)
+			}
+			if a.Pos != tt.pos {
+				t.Fatalf("pos = %+v, want %+v", a.Pos, tt.pos)
+			}
+		})
+	}
+}
+
+func TestAST_Write_StripsBOM(t *testing.T) {
+	var a AST
+	in := []byte("\uFEFFkey")
+	n, err := a.Write(in)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if n != len(in) {
+		t.Fatalf("Write() n = %d, want %d", n, len(in))
+	}
+	if got := string(a.B); got != "key" {
+		t.Fatalf("source = %q, want %q", got, "key")
+	}
+}
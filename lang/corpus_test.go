@@ -0,0 +1,68 @@
+//go:build goexperiment.jsonv2
+
+package lang
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/corpus")
+
+// TestAST_Corpus_MatchesGolden parses each testdata/corpus/*.aenv fixture and
+// compares its serialized AST against the adjacent .json golden file, so that
+// grammar changes cannot silently alter how existing syntax is parsed.
+//
+// Run with -update to regenerate the golden files after an intended change,
+// then review the diff.
+func TestAST_Corpus_MatchesGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "corpus", "*.aenv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures in testdata/corpus")
+	}
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".aenv")
+		t.Run(name, func(t *testing.T) {
+			a, err := ParseFiles(t.Context(), []string{fixture})
+			if err != nil {
+				t.Fatalf("ParseFiles(%q) error = %v", fixture, err)
+			}
+			// Record provenance by file name only, so golden files do not depend
+			// on the platform's path separator.
+			for i := range a.Sources {
+				a.Sources[i].Path = filepath.Base(a.Sources[i].Path)
+			}
+			// Encode with the same encoder as AST.String, indented for review.
+			got, err := json.Marshal(a, jsontext.WithIndent("  "))
+			if err != nil {
+				t.Fatalf("marshal AST: %v", err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(fixture, ".aenv") + ".json"
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("AST of %s differs from %s\ngot:\n%s\nwant:\n%s",
+					fixture, golden, got, want)
+			}
+		})
+	}
+}
@@ -2,15 +2,26 @@ package lang
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ardnew/aenv/log"
 )
 
 const parseErrorContextWidth = 48
 
+var ErrInputTooLarge = errors.New("input too large")
+
+// errf is a helper that appends a formatted message to a wrapped error.
+func errf(err error, template string, args ...any) error {
+	template = "%w: " + template
+	args = append([]any{err}, args...)
+	return fmt.Errorf(template, args...)
+}
+
 type ParseError struct {
 	Err error
 	Pos Pos
@@ -35,8 +46,13 @@ func ContextualParseError(err error, pos Pos, r io.Reader) error {
 		panic(fmt.Errorf("%w: "+m+": %w", err, serr))
 	}
 
+	// Columns count runes, not bytes (see AST.scan). ScanLines drops the "\r" of
+	// a CRLF line ending, and the byte order mark is not part of the source.
 	line := scan.Text()
-	if pos.Column-1 > int64(len(line)) {
+	if pos.Line == 1 {
+		line = strings.TrimPrefix(line, "\uFEFF")
+	}
+	if pos.Column-1 > int64(utf8.RuneCountInString(line)) {
 		const m = `failed to index source line for error reporting`
 		log.Error(log.Attrs("error", err, "pos", pos), m)
 		panic(fmt.Errorf("%w: "+m, err))
@@ -61,55 +77,40 @@ func (e *ParseError) Snippet() string {
 	return e.srcContext
 }
 
+// buildContext returns the part of line around pos that fits in width
+// display cells, followed by a marker line with an arrow under pos.
 func buildContext(line string, pos Pos, width int64) string {
-	// Capture a span of the line around the error position, with a fixed width.
-	var span = struct {
-		beg, end           int64
-		truncBeg, truncEnd bool
-	}{
-		beg: pos.Column - 1 - (width / 2),
-		end: pos.Column - 1 + (width / 2),
-	}
-	if span.beg < 0 {
-		span.end += -span.beg
-		span.beg = 0
+	// Split the line at the error column, which may be one past the last rune
+	// (e.g., an unexpected end of line).
+	runes := []rune(line)
+	col := min(max(pos.Column-1, 0), int64(len(runes)))
+	before, after := string(runes[:col]), string(runes[col:])
+
+	// Center the error column in the span, giving either side the cells the
+	// other does not need.
+	lcells := int(width / 2)
+	rcells := int(width) - lcells
+	if w := StringWidth(after); w < rcells {
+		lcells, rcells = lcells+rcells-w, w
 	}
-	if span.end > int64(len(line)) {
-		span.beg -= span.end - int64(len(line))
-		span.end = int64(len(line))
-		if span.beg < 0 {
-			span.beg = 0
-		}
+	if w := StringWidth(before); w < lcells {
+		lcells, rcells = w, rcells+lcells-w
 	}
 
-	span.truncBeg = span.beg > 0
-	span.truncEnd = span.end < int64(len(line))
-
-	// Convert to rune slice for simpler editing.
-	runes := []rune(line)
-
 	// Replace truncated text with ellipsis if it's not just whitespace.
-	if span.truncBeg && strings.TrimSpace(string(runes[:span.beg])) != "" {
-		runes[span.beg] = '…'
+	head := TruncateWidthLeft(before, lcells, "")
+	if strings.TrimSpace(before[:len(before)-len(head)]) != "" {
+		head = TruncateWidthLeft(before, lcells, Ellipsis)
 	}
-	if span.truncEnd && strings.TrimSpace(string(runes[span.end:])) != "" {
-		runes[span.end-1] = '…'
-	}
-
-	var sb strings.Builder
-	sb.WriteString(string(runes[span.beg:span.end]))
-	sb.WriteByte('\n')
-
-	// Add a marker line with an arrow pointing to the error column.
-	for i := span.beg; i < span.end; i++ {
-		if i == pos.Column-1 {
-			sb.WriteRune('↑')
-		} else {
-			sb.WriteByte(' ')
-		}
+	rest := TruncateWidth(after, rcells, "")
+	if strings.TrimSpace(after[len(rest):]) != "" {
+		rest = TruncateWidth(after, rcells, Ellipsis)
 	}
 
-	return sb.String()
+	// The arrow is padded by display width rather than one cell per rune, so
+	// it stays aligned after wide (e.g., CJK) or zero-width (e.g., combining)
+	// runes.
+	return head + rest + "\n" + strings.Repeat(" ", StringWidth(head)) + "↑"
 }
 
 //123456789012345678901234567890123456789012345678901234567890
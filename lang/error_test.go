@@ -0,0 +1,133 @@
+package lang
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+var errTestParse = errors.New("test parse error")
+
+func TestContextualParseError_Snippet_MultiByteLine(t *testing.T) {
+	line := strings.Repeat("αβγδ", 20) // 80 runes, 160 bytes
+	tests := []struct {
+		name   string
+		column int64
+	}{
+		{name: "start", column: 1},
+		{name: "middle", column: 40},
+		{name: "end", column: 80},
+		{name: "past last rune", column: 81},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := Pos{Line: 1, Column: tt.column}
+			err := ContextualParseError(errTestParse, pos, strings.NewReader(line))
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ContextualParseError() = %T, want *ParseError", err)
+			}
+			snippet := perr.Snippet()
+			if !utf8.ValidString(snippet) {
+				t.Fatalf("Snippet() = %q, want valid UTF-8", snippet)
+			}
+			text, marker, ok := strings.Cut(snippet, "\n")
+			if !ok {
+				t.Fatalf("Snippet() = %q, want source and marker lines", snippet)
+			}
+			if got := utf8.RuneCountInString(text); got != parseErrorContextWidth {
+				t.Fatalf("source line width = %d runes, want %d", got, parseErrorContextWidth)
+			}
+			if !strings.Contains(marker, "↑") {
+				t.Fatalf("marker line = %q, want arrow", marker)
+			}
+		})
+	}
+}
+
+func TestContextualParseError_Snippet_WideLineFitsWidth(t *testing.T) {
+	line := strings.Repeat("漢字", 40) // 80 runes, 160 cells
+	for _, column := range []int64{1, 40, 80, 81} {
+		pos := Pos{Line: 1, Column: column}
+		err := ContextualParseError(errTestParse, pos, strings.NewReader(line))
+
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("ContextualParseError() = %T, want *ParseError", err)
+		}
+		text, marker, _ := strings.Cut(perr.Snippet(), "\n")
+		if got := runewidth.StringWidth(text); got > parseErrorContextWidth {
+			t.Fatalf("column %d: source line width = %d cells, want <= %d",
+				column, got, parseErrorContextWidth)
+		}
+		if got := runewidth.StringWidth(marker); got > parseErrorContextWidth+1 {
+			t.Fatalf("column %d: marker line width = %d cells, want <= %d",
+				column, got, parseErrorContextWidth+1)
+		}
+	}
+}
+
+func TestContextualParseError_Snippet_MarkerAlignsDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		column int64
+	}{
+		{name: "ascii", line: "key : value", column: 7},
+		{name: "wide", line: "漢字漢字 : value", column: 6},
+		{name: "combining", line: "e\u0301e\u0301 : value", column: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := Pos{Line: 1, Column: tt.column}
+			err := ContextualParseError(errTestParse, pos, strings.NewReader(tt.line))
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ContextualParseError() = %T, want *ParseError", err)
+			}
+			text, marker, _ := strings.Cut(perr.Snippet(), "\n")
+			before, _, ok := strings.Cut(marker, "↑")
+			if !ok {
+				t.Fatalf("marker line = %q, want arrow", marker)
+			}
+			prefix := string([]rune(text)[:tt.column-1])
+			if got, want := runewidth.StringWidth(before), runewidth.StringWidth(prefix); got != want {
+				t.Fatalf("arrow at cell %d, want %d (under %q)", got, want, []rune(text)[tt.column-1])
+			}
+		})
+	}
+}
+
+func TestContextualParseError_Snippet_BOMAndCRLF(t *testing.T) {
+	src := "\uFEFFab : 1\r\ncd : 2\r\n"
+	tests := []struct {
+		name string
+		pos  Pos
+		text string
+	}{
+		{name: "first line", pos: Pos{Line: 1, Column: 4}, text: "ab : 1"},
+		{name: "second line end", pos: Pos{Line: 2, Column: 7}, text: "cd : 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ContextualParseError(errTestParse, tt.pos, strings.NewReader(src))
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ContextualParseError() = %T, want *ParseError", err)
+			}
+			text, marker, _ := strings.Cut(perr.Snippet(), "\n")
+			if text != tt.text {
+				t.Fatalf("snippet source = %q, want %q", text, tt.text)
+			}
+			if got := strings.Index(marker, "↑"); got != int(tt.pos.Column-1) {
+				t.Fatalf("arrow at byte %d, want %d", got, tt.pos.Column-1)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+//go:build goexperiment.jsonv2
+
+package lang
+
+// FeatureSet describes what an [AST] supports as configured, so that
+// embedders such as editors and playgrounds can adapt their completion and
+// validation to the engine rather than assuming a particular build.
+type FeatureSet struct {
+	// Version is the newest language version accepted by a #lang pragma.
+	Version int
+	// MaxInputSize is the source size limit in bytes, or 0 if unlimited.
+	MaxInputSize int64
+}
+
+// Features returns the features of an [AST] configured by opts.
+func Features(opts ...Option) FeatureSet {
+	return New(opts...).Features()
+}
+
+// Features returns the features of the receiver's configuration.
+func (a *AST) Features() FeatureSet {
+	return FeatureSet{
+		Version:      Version,
+		MaxInputSize: max(0, a.maxInputSize),
+	}
+}
@@ -0,0 +1,24 @@
+//go:build goexperiment.jsonv2
+
+package lang
+
+import "testing"
+
+func TestFeatures_ReflectsOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want FeatureSet
+	}{
+		{name: "default", want: FeatureSet{Version: Version}},
+		{name: "limited", opts: []Option{WithMaxInputSize(64)}, want: FeatureSet{Version: Version, MaxInputSize: 64}},
+		{name: "negative", opts: []Option{WithMaxInputSize(-1)}, want: FeatureSet{Version: Version}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Features(tt.opts...); got != tt.want {
+				t.Fatalf("Features() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
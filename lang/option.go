@@ -0,0 +1,25 @@
+//go:build goexperiment.jsonv2
+
+package lang
+
+// Option configures an [AST].
+type Option func(*AST)
+
+// New returns an [AST] configured by opts.
+func New(opts ...Option) *AST {
+	a := new(AST)
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// WithMaxInputSize limits the total number of source bytes an [AST] accepts.
+// Input that would exceed the limit is rejected with [ErrInputTooLarge] before
+// it is buffered. A limit of zero or less disables the check.
+//
+// Token count and nesting depth are not limited, because the AST does not yet
+// tokenize its source.
+func WithMaxInputSize(n int64) Option {
+	return func(a *AST) { a.maxInputSize = n }
+}
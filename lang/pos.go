@@ -44,3 +44,33 @@ func (p Pos) Sub(q Pos) Pos {
 func (p Pos) String() string {
 	return fmt.Sprintf("%d:%d+%d", p.Line, p.Column, p.Offset)
 }
+
+// Span refers to a contiguous range of a byte stream, from the Start position
+// (inclusive) to the End position (exclusive).
+//
+// Like [Pos], the zero value is an invalid span.
+type Span struct {
+	Start, End Pos
+}
+
+// IsZero returns whether both endpoints of the span are the invalid zero
+// value.
+func (s Span) IsZero() bool {
+	return s.Start.IsZero() && s.End.IsZero()
+}
+
+// Len returns the number of bytes covered by the span.
+func (s Span) Len() int64 {
+	return s.End.Offset - s.Start.Offset
+}
+
+// Contains returns whether the byte offset of p lies within the span.
+func (s Span) Contains(p Pos) bool {
+	return s.Start.Offset <= p.Offset && p.Offset < s.End.Offset
+}
+
+// String returns a human-readable span string, e.g. "3:15+42..3:20+47",
+// formatting each endpoint as described by [Pos.String].
+func (s Span) String() string {
+	return s.Start.String() + ".." + s.End.String()
+}
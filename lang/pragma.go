@@ -0,0 +1,85 @@
+package lang
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Version is the newest version of the aenv language understood by this
+// package. A file may declare the version it requires with a pragma on its
+// first line:
+//
+//	#lang aenv/1
+//
+// Sources without a pragma are parsed as the current version. The pragma is
+// checked at the start of every source: each file read by [ParseFiles], and
+// the stream written to an [AST].
+const Version = 1
+
+var (
+	ErrInvalidPragma      = errors.New("invalid #lang pragma")
+	ErrUnsupportedVersion = errors.New("unsupported language version")
+)
+
+const (
+	pragmaPrefix = "#lang"
+	pragmaLang   = "aenv"
+
+	// maxPragmaLen bounds the length of a pragma line, so that a source
+	// starting with "#lang" is never scanned far for the end of its line.
+	maxPragmaLen = 1024
+)
+
+// checkPragma validates the #lang pragma on the first line of src, if any.
+// The pragma remains part of the parsed source.
+func checkPragma(src []byte) error {
+	if !bytes.HasPrefix(src, []byte(pragmaPrefix)) {
+		return nil
+	}
+	head := src[:min(len(src), maxPragmaLen+1)]
+	line, _, found := bytes.Cut(head, []byte{'\n'})
+	if !found && len(line) > maxPragmaLen {
+		return ContextualParseError(
+			errf(ErrInvalidPragma, "line exceeds %d bytes", maxPragmaLen),
+			Pos{Offset: 0, Line: 1, Column: 1}, bytes.NewReader(line))
+	}
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	text := string(line)
+	rest := text[len(pragmaPrefix):]
+	arg := strings.TrimLeft(rest, " \t")
+	if arg == rest && arg != "" {
+		return nil // e.g., "#language", not a pragma
+	}
+	arg = strings.TrimRight(arg, " \t")
+
+	// Report the position of the pragma's argument, relative to the source.
+	col := int64(utf8.RuneCountInString(text[:len(text)-len(arg)])) + 1
+	pos := Pos{Offset: int64(len(text) - len(arg)), Line: 1, Column: col}
+	fail := func(err error) error {
+		return ContextualParseError(err, pos, strings.NewReader(text))
+	}
+
+	if arg == "" {
+		return fail(errf(ErrInvalidPragma, "missing %s/<version>", pragmaLang))
+	}
+	lang, version, found := strings.Cut(arg, "/")
+	if lang != pragmaLang {
+		return fail(errf(ErrUnsupportedVersion, "%q is not %s", arg, pragmaLang))
+	}
+	if !found || strings.TrimSpace(version) == "" {
+		return fail(errf(ErrInvalidPragma, "missing version, want %s/<version>", pragmaLang))
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(version))
+	if err != nil || n < 1 {
+		return fail(errf(ErrInvalidPragma, "want %s/<version>, got %q", pragmaLang, arg))
+	}
+	if n > Version {
+		return fail(errf(ErrUnsupportedVersion,
+			"file requires %s/%d, this aenv supports up to %s/%d",
+			pragmaLang, n, pragmaLang, Version))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+//go:build goexperiment.jsonv2
+
+package lang
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFiles_LangPragma(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+		wantPos Pos
+	}{
+		{name: "absent", content: "a : 1\n"},
+		{name: "current", content: "#lang aenv/1\na : 1\n"},
+		{name: "crlf", content: "#lang aenv/1\r\n"},
+		{name: "bom", content: "\uFEFF#lang aenv/1\n"},
+		{name: "not pragma", content: "#language\n"},
+		{
+			name:    "newer",
+			content: "#lang aenv/2\n",
+			wantErr: ErrUnsupportedVersion,
+			wantPos: Pos{Offset: 6, Line: 1, Column: 7},
+		},
+		{
+			name:    "other language",
+			content: "#lang  toml/1\n",
+			wantErr: ErrUnsupportedVersion,
+			wantPos: Pos{Offset: 7, Line: 1, Column: 8},
+		},
+		{
+			name:    "malformed",
+			content: "#lang aenv/x",
+			wantErr: ErrInvalidPragma,
+			wantPos: Pos{Offset: 6, Line: 1, Column: 7},
+		},
+		{
+			name:    "missing slash version",
+			content: "#lang aenv\n",
+			wantErr: ErrInvalidPragma,
+			wantPos: Pos{Offset: 6, Line: 1, Column: 7},
+		},
+		{
+			name:    "wide separator",
+			content: "#lang" + strings.Repeat(" ", 60) + "aenv/2\n",
+			wantErr: ErrUnsupportedVersion,
+			wantPos: Pos{Offset: 65, Line: 1, Column: 66},
+		},
+		{
+			name:    "line too long",
+			content: "#lang aenv/1 " + strings.Repeat(" ", maxPragmaLen) + "\n",
+			wantErr: ErrInvalidPragma,
+			wantPos: Pos{Offset: 0, Line: 1, Column: 1},
+		},
+		{
+			name:    "missing version",
+			content: "#lang\n",
+			wantErr: ErrInvalidPragma,
+			wantPos: Pos{Offset: 5, Line: 1, Column: 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeSource(t, "src.aenv", tt.content)
+			a, err := ParseFiles(t.Context(), []string{path})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ParseFiles() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFiles() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.HasPrefix(err.Error(), path+": ") {
+				t.Fatalf("ParseFiles() error = %q, want path prefix", err)
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) || perr.Pos != tt.wantPos {
+				t.Fatalf("ParseFiles() error = %#v, want *ParseError at %v", err, tt.wantPos)
+			}
+			if len(a.Sources) != 0 {
+				t.Fatalf("Sources = %+v, want rejected file not recorded", a.Sources)
+			}
+		})
+	}
+}
+
+func TestAST_Write_LangPragma(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "current", input: "#lang aenv/1\na : 1\n"},
+		{name: "bom", input: "\uFEFF#lang aenv/1\n"},
+		{name: "newer", input: "#lang aenv/2\na : 1\n", wantErr: ErrUnsupportedVersion},
+		{name: "malformed", input: "#lang aenv\n", wantErr: ErrInvalidPragma},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New()
+			n, err := a.Write([]byte(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Write() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && (n != 0 || len(a.B) != 0) {
+				t.Fatalf("Write() buffered %q (n=%d), want nothing", a.B, n)
+			}
+		})
+	}
+}
+
+func TestAST_Parse_LangPragmaAtStreamStart(t *testing.T) {
+	var a AST
+	if _, err := a.parse(strings.NewReader("#lang aenv/2\n")); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("parse() error = %v, want %v", err, ErrUnsupportedVersion)
+	}
+
+	// Only the start of the stream is a pragma; later chunks are source.
+	if _, err := a.parse(strings.NewReader("a\n")); err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if _, err := a.parse(strings.NewReader("#lang aenv/2\n")); err != nil {
+		t.Fatalf("parse() mid-stream error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,109 @@
+//go:build goexperiment.jsonv2
+
+package lang
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ardnew/aenv/log"
+)
+
+// Source records the provenance of a contiguous range of an [AST]'s buffered
+// source: the file it was read from, and the span it occupies in the merged
+// stream.
+type Source struct {
+	Path string `json:"path"`
+	Span Span   `json:"span"`
+}
+
+// Rel converts p, a position in the merged stream, to a position relative to
+// the start of the receiver's file.
+func (s Source) Rel(p Pos) Pos {
+	rel := Pos{
+		Offset: p.Offset - s.Span.Start.Offset,
+		Line:   p.Line - s.Span.Start.Line + 1,
+		Column: p.Column,
+	}
+	if p.Line == s.Span.Start.Line {
+		// Files read by ParseFiles always begin a line, but a Source may
+		// describe any span.
+		rel.Column = p.Column - s.Span.Start.Column + 1
+	}
+	return rel
+}
+
+// ParseFiles reads each file in paths, in order, into a single [AST] whose
+// Sources record which file each range of the merged source came from. The
+// AST is configured by opts, which apply to the merged source as a whole.
+//
+// A newline is inserted after any file that lacks a trailing newline, so that
+// the last line of one file is never joined to the first line of the next.
+// The inserted newline belongs to no file's span.
+//
+// It stops at the first file that cannot be read, whose #lang pragma requires
+// an unsupported language version (see [Version]), or once ctx is done.
+// Errors from reading a file are prefixed with its path.
+func ParseFiles(ctx context.Context, paths []string, opts ...Option) (*AST, error) {
+	a := New(opts...)
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return a, err
+		}
+		if err := a.parseFile(path); err != nil {
+			return a, err
+		}
+	}
+	log.Debug(log.Attrs("count", len(a.Sources), "pos", a.Pos), "parsed files")
+	return a, nil
+}
+
+// Source returns the provenance of the file containing p and true, or false if
+// p was not read from a file.
+func (a *AST) Source(p Pos) (Source, bool) {
+	for _, src := range a.Sources {
+		if src.Span.Contains(p) {
+			return src, true
+		}
+	}
+	return Source{}, false
+}
+
+func (a *AST) parseFile(path string) (err error) {
+	// Restore the AST if the file fails, so that it holds only whole files,
+	// each recorded in Sources.
+	n, pos, sources := len(a.B), a.Pos, len(a.Sources)
+	defer func() {
+		if err != nil {
+			a.B, a.Pos, a.Sources = a.B[:n], pos, a.Sources[:sources]
+		}
+	}()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if n > 0 && a.B[n-1] != '\n' {
+		if err := a.checkInputSize(int64(n + 1)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		a.scan([]byte{'\n'})
+	}
+	start := a.Pos
+	if start.IsZero() {
+		start = Pos{Line: 1, Column: 1}
+	}
+	if _, err := a.parseSource(f, true); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	log.Trace(log.Attrs("path", path, "beg", start, "end", a.Pos), "parsed file")
+	a.Sources = append(a.Sources, Source{Path: path, Span: Span{Start: start, End: a.Pos}})
+	return nil
+}
@@ -0,0 +1,132 @@
+//go:build goexperiment.jsonv2
+
+package lang
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSource(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestParseFiles_RecordsProvenance(t *testing.T) {
+	first := writeSource(t, "first", "a : 1\nbb")
+	empty := writeSource(t, "empty", "")
+	second := writeSource(t, "second", "c\nd : 2\n")
+
+	a, err := ParseFiles(t.Context(), []string{first, empty, second})
+	if err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+	if got, want := string(a.B), "a : 1\nbb\nc\nd : 2\n"; got != want {
+		t.Fatalf("merged source = %q, want %q", got, want)
+	}
+
+	want := []Source{
+		{Path: first, Span: Span{Start: Pos{0, 1, 1}, End: Pos{8, 2, 3}}},
+		{Path: empty, Span: Span{Start: Pos{9, 3, 1}, End: Pos{9, 3, 1}}},
+		{Path: second, Span: Span{Start: Pos{9, 3, 1}, End: Pos{17, 5, 1}}},
+	}
+	if len(a.Sources) != len(want) {
+		t.Fatalf("Sources = %+v, want %+v", a.Sources, want)
+	}
+	for i := range want {
+		if a.Sources[i] != want[i] {
+			t.Fatalf("Sources[%d] = %+v, want %+v", i, a.Sources[i], want[i])
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pos     Pos
+		path    string
+		rel     Pos
+		missing bool
+	}{
+		{name: "first file", pos: Pos{6, 2, 1}, path: first, rel: Pos{6, 2, 1}},
+		{name: "inserted newline", pos: Pos{8, 2, 3}, missing: true},
+		{name: "second file first line", pos: Pos{9, 3, 1}, path: second, rel: Pos{0, 1, 1}},
+		{name: "second file later line", pos: Pos{13, 4, 3}, path: second, rel: Pos{4, 2, 3}},
+		{name: "end of stream", pos: Pos{17, 5, 1}, missing: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, ok := a.Source(tt.pos)
+			if ok == tt.missing {
+				t.Fatalf("Source(%v) ok = %v, want %v", tt.pos, ok, !tt.missing)
+			}
+			if tt.missing {
+				return
+			}
+			if src.Path != tt.path {
+				t.Fatalf("Source(%v).Path = %q, want %q", tt.pos, src.Path, tt.path)
+			}
+			if got := src.Rel(tt.pos); got != tt.rel {
+				t.Fatalf("Rel(%v) = %v, want %v", tt.pos, got, tt.rel)
+			}
+		})
+	}
+}
+
+func TestParseFiles_StripsEachBOM(t *testing.T) {
+	first := writeSource(t, "first", "\uFEFFa\r\n")
+	second := writeSource(t, "second", "\uFEFFb")
+
+	a, err := ParseFiles(t.Context(), []string{first, second})
+	if err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+	if got, want := string(a.B), "a\r\nb"; got != want {
+		t.Fatalf("merged source = %q, want %q", got, want)
+	}
+	if got, want := a.Sources[1].Span.Start, (Pos{3, 2, 1}); got != want {
+		t.Fatalf("second file start = %v, want %v", got, want)
+	}
+}
+
+func TestParseFiles_Options(t *testing.T) {
+	first := writeSource(t, "first", "1234")
+	second := writeSource(t, "second", "5678")
+
+	a, err := ParseFiles(t.Context(), []string{first, second}, WithMaxInputSize(8))
+	if !errors.Is(err, ErrInputTooLarge) {
+		t.Fatalf("ParseFiles() error = %v, want %v", err, ErrInputTooLarge)
+	}
+	if !strings.HasPrefix(err.Error(), second+": ") {
+		t.Fatalf("ParseFiles() error = %q, want prefix %q", err, second+": ")
+	}
+	if got := string(a.B); got != "1234" {
+		t.Fatalf("merged source = %q, want only the first file", got)
+	}
+}
+
+func TestParseFiles_StopsAtError(t *testing.T) {
+	good := writeSource(t, "good", "x")
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	a, err := ParseFiles(t.Context(), []string{good, missing, good})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("ParseFiles() error = %v, want %v", err, fs.ErrNotExist)
+	}
+	if len(a.Sources) != 1 {
+		t.Fatalf("Sources = %+v, want only the first file", a.Sources)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := ParseFiles(ctx, []string{good}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ParseFiles(canceled) error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,57 @@
+package lang
+
+import "github.com/mattn/go-runewidth"
+
+// Ellipsis marks text removed by [TruncateWidth] and [TruncateWidthLeft].
+const Ellipsis = "…"
+
+// StringWidth returns the number of terminal cells needed to display s. Each
+// rune is measured on its own, so the result agrees with padding built rune by
+// rune (e.g., a marker under a column of s).
+func StringWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runewidth.RuneWidth(r)
+	}
+	return n
+}
+
+// TruncateWidth returns the longest prefix of s that fits in width cells (see
+// [StringWidth]) together with tail, followed by tail. If s already fits, it
+// is returned unchanged. Runes are never split, so the result may be narrower
+// than width when a wide rune straddles the cut.
+func TruncateWidth(s string, width int, tail string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	budget := width - StringWidth(tail)
+	runes := []rune(s)
+	cut, used := 0, 0
+	for cut < len(runes) {
+		w := runewidth.RuneWidth(runes[cut])
+		if used+w > budget {
+			break
+		}
+		cut, used = cut+1, used+w
+	}
+	return string(runes[:cut]) + tail
+}
+
+// TruncateWidthLeft is like [TruncateWidth], but keeps the longest suffix of
+// s, preceded by tail.
+func TruncateWidthLeft(s string, width int, tail string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	budget := width - StringWidth(tail)
+	runes := []rune(s)
+	cut, used := len(runes), 0
+	for cut > 0 {
+		w := runewidth.RuneWidth(runes[cut-1])
+		if used+w > budget {
+			break
+		}
+		cut, used = cut-1, used+w
+	}
+	return tail + string(runes[cut:])
+}
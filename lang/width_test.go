@@ -0,0 +1,37 @@
+package lang
+
+import "testing"
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		width    int
+		want     string
+		wantLeft string
+	}{
+		{name: "fits", s: "abc", width: 3, want: "abc", wantLeft: "abc"},
+		{name: "ascii", s: "abcdef", width: 4, want: "abc…", wantLeft: "…def"},
+		{name: "wide", s: "漢字漢字", width: 5, want: "漢字…", wantLeft: "…漢字"},
+		{name: "wide straddles cut", s: "漢字漢字", width: 4, want: "漢…", wantLeft: "…字"},
+		{name: "zero width", s: "", width: 0, want: "", wantLeft: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateWidth(tt.s, tt.width, Ellipsis)
+			if got != tt.want {
+				t.Fatalf("TruncateWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+			if n := StringWidth(got); n > tt.width {
+				t.Fatalf("StringWidth(%q) = %d, want <= %d", got, n, tt.width)
+			}
+			left := TruncateWidthLeft(tt.s, tt.width, Ellipsis)
+			if left != tt.wantLeft {
+				t.Fatalf("TruncateWidthLeft(%q, %d) = %q, want %q", tt.s, tt.width, left, tt.wantLeft)
+			}
+			if n := StringWidth(left); n > tt.width {
+				t.Fatalf("StringWidth(%q) = %d, want <= %d", left, n, tt.width)
+			}
+		})
+	}
+}
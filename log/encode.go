@@ -457,10 +457,11 @@ func appendJSONField(buf *bytes.Buffer, first *bool, key, value string) {
 	appendJSONString(buf, value)
 }
 
-// appendJSONKey writes a JSON object key using Go-syntax quoting, reusing the
-// buffer's spare capacity to avoid a per-key allocation.
+// appendJSONKey writes a JSON object key. Keys use the same quoting as string
+// values: Go-syntax quoting would emit escapes such as \x00 or \xff that are
+// not valid JSON.
 func appendJSONKey(buf *bytes.Buffer, key string) {
-	buf.Write(strconv.AppendQuote(buf.AvailableBuffer(), key))
+	appendJSONString(buf, key)
 }
 
 // appendJSONValue writes value as a JSON token, encoding primitives directly to
@@ -527,7 +528,7 @@ func appendJSONString(buf *bytes.Buffer, s string) {
 			if start < i {
 				buf.WriteString(s[start:i])
 			}
-			buf.WriteString(`\ufffd`)
+			buf.WriteRune(utf8.RuneError)
 			i += size
 			start = i
 			continue
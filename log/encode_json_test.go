@@ -3,12 +3,36 @@ package log
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
-// TestAppendJSONString_MatchesEncodingJSON locks the direct JSON string encoder
-// to byte-identical output with encoding/json's default (HTML-escaping) encoder.
-func TestAppendJSONString_MatchesEncodingJSON(t *testing.T) {
+// checkJSONString fails t unless got is a valid JSON string that decodes to
+// in, with malformed UTF-8 replaced by U+FFFD. It compares decoded values
+// rather than bytes, since encoding/json's exact output differs between
+// builds with and without GOEXPERIMENT=jsonv2.
+func checkJSONString(t *testing.T, fn, in string, got []byte) {
+	t.Helper()
+	if !json.Valid(got) {
+		t.Fatalf("%s(%q) = %s, want valid JSON", fn, in, got)
+	}
+	var dec string
+	if err := json.Unmarshal(got, &dec); err != nil {
+		t.Fatalf("%s(%q) = %s, decode error = %v", fn, in, got, err)
+	}
+	if want := strings.ToValidUTF8(in, "\uFFFD"); dec != want {
+		t.Fatalf("%s(%q) decodes to %q, want %q", fn, in, dec, want)
+	}
+	// HTML-significant characters and JavaScript line terminators are always
+	// escaped, so that log lines are safe to embed in HTML and JavaScript.
+	if i := bytes.IndexAny(got, "<>&\u2028\u2029"); i >= 0 {
+		t.Fatalf("%s(%q) = %s, want %q escaped", fn, in, got, got[i:])
+	}
+}
+
+// TestAppendJSONString_RoundTrips locks the direct JSON string encoder to
+// output that decodes back to its input.
+func TestAppendJSONString_RoundTrips(t *testing.T) {
 	cases := []string{
 		"",
 		"plain",
@@ -25,15 +49,29 @@ func TestAppendJSONString_MatchesEncodingJSON(t *testing.T) {
 	}
 	for _, in := range cases {
 		t.Run(in, func(t *testing.T) {
-			want, err := json.Marshal(in)
-			if err != nil {
-				t.Fatalf("json.Marshal(%q) error = %v", in, err)
-			}
 			var buf bytes.Buffer
 			appendJSONString(&buf, in)
-			if got := buf.Bytes(); !bytes.Equal(got, want) {
-				t.Fatalf("appendJSONString(%q)\nwant: %s\ngot:  %s", in, want, got)
-			}
+			checkJSONString(t, "appendJSONString", in, buf.Bytes())
+		})
+	}
+}
+
+// TestAppendJSONKey_RoundTrips ensures object keys are quoted as JSON
+// strings rather than Go string literals, which would emit invalid escapes for
+// control bytes and malformed UTF-8.
+func TestAppendJSONKey_RoundTrips(t *testing.T) {
+	cases := []string{
+		"plain",
+		"kebab-case",
+		"unicode-é☃",
+		"control\x00\x07",
+		"invalid\xffutf8",
+	}
+	for _, in := range cases {
+		t.Run(in, func(t *testing.T) {
+			var buf bytes.Buffer
+			appendJSONKey(&buf, in)
+			checkJSONString(t, "appendJSONKey", in, buf.Bytes())
 		})
 	}
 }